@@ -0,0 +1,239 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ActionStep is one action of an ActionSequence.
+type ActionStep struct {
+	Action SandboxUseActionDtoActionOneOf
+
+	// IncludeScreenShot and IncludeCursorPosition override RunOptions for this step, nil to use them
+	IncludeScreenShot     *bool
+	IncludeCursorPosition *bool
+}
+
+// ActionSequence builds a list of sandbox actions, see Actions.
+type ActionSequence struct {
+	steps []ActionStep
+}
+
+// Actions starts an empty action sequence. Coordinates are in pixels.
+//
+//	results, err := lybic.Actions().
+//		Click(100, 200).
+//		Type("hello", true).
+//		Hotkey("ctrl+s").
+//		Wait(500).
+//		Screenshot().
+//		Run(ctx, client, sandboxId, nil)
+func Actions() *ActionSequence {
+	return &ActionSequence{}
+}
+
+// Add appends any sandbox action to the sequence.
+func (s *ActionSequence) Add(action SandboxUseActionDtoActionOneOf) *ActionSequence {
+	s.steps = append(s.steps, ActionStep{Action: action})
+	return s
+}
+
+// Click clicks the left button at (x, y).
+func (s *ActionSequence) Click(x, y int) *ActionSequence {
+	return s.Add(NewMouseClickAction(NewPixelLength(x), NewPixelLength(y), 1))
+}
+
+// RightClick clicks the right button at (x, y).
+func (s *ActionSequence) RightClick(x, y int) *ActionSequence {
+	return s.Add(NewMouseClickAction(NewPixelLength(x), NewPixelLength(y), 2))
+}
+
+// DoubleClick double clicks the left button at (x, y).
+func (s *ActionSequence) DoubleClick(x, y int) *ActionSequence {
+	return s.Add(NewMouseDoubleClickAction(NewPixelLength(x), NewPixelLength(y), 1))
+}
+
+// Move moves the mouse to (x, y).
+func (s *ActionSequence) Move(x, y int) *ActionSequence {
+	return s.Add(NewMouseMoveAction(NewPixelLength(x), NewPixelLength(y)))
+}
+
+// Scroll scrolls at (x, y) by the given number of steps.
+func (s *ActionSequence) Scroll(x, y, stepVertical, stepHorizontal int) *ActionSequence {
+	return s.Add(NewMouseScrollAction(NewPixelLength(x), NewPixelLength(y), stepVertical, stepHorizontal))
+}
+
+// Drag drags with the left button from (startX, startY) to (endX, endY).
+func (s *ActionSequence) Drag(startX, startY, endX, endY int) *ActionSequence {
+	return s.Add(NewMouseDragAction(NewPixelLength(startX), NewPixelLength(startY), NewPixelLength(endX), NewPixelLength(endY), 1))
+}
+
+// AddAll appends several sandbox actions to the sequence, e.g. the result of DragPath.
+func (s *ActionSequence) AddAll(actions ...SandboxUseActionDtoActionOneOf) *ActionSequence {
+	for _, action := range actions {
+		s.Add(action)
+	}
+	return s
+}
+
+// MoveAlong moves the mouse from one point to another along a human-like path.
+func (s *ActionSequence) MoveAlong(path MousePath, from, to PathPoint) *ActionSequence {
+	return s.AddAll(path.Moves(from, to)...)
+}
+
+// Type types the content, newlines are sent as Enter if treatNewLineAsEnter is set.
+func (s *ActionSequence) Type(content string, treatNewLineAsEnter bool) *ActionSequence {
+	return s.Add(NewKeyboardTypeAction(content, treatNewLineAsEnter))
+}
+
+// Hotkey presses a key combination, e.g. "ctrl+s".
+func (s *ActionSequence) Hotkey(keys string) *ActionSequence {
+	return s.Add(NewKeyboardHotkeyAction(keys))
+}
+
+// KeyDown presses a key without releasing it.
+func (s *ActionSequence) KeyDown(key string) *ActionSequence {
+	return s.Add(NewKeyDownAction(key))
+}
+
+// KeyUp releases a key.
+func (s *ActionSequence) KeyUp(key string) *ActionSequence {
+	return s.Add(NewKeyUpAction(key))
+}
+
+// Wait waits for the duration in milliseconds.
+func (s *ActionSequence) Wait(ms int) *ActionSequence {
+	return s.Add(NewWaitAction(ms))
+}
+
+// Screenshot takes a screenshot, the step always includes the screenshot url.
+func (s *ActionSequence) Screenshot() *ActionSequence {
+	s.Add(NewScreenshotAction())
+	return s.WithScreenShot(true)
+}
+
+// WithScreenShot sets whether the response of the last step includes the screenshot url.
+func (s *ActionSequence) WithScreenShot(include bool) *ActionSequence {
+	if len(s.steps) > 0 {
+		s.steps[len(s.steps)-1].IncludeScreenShot = &include
+	}
+	return s
+}
+
+// WithCursorPosition sets whether the response of the last step includes the cursor position.
+func (s *ActionSequence) WithCursorPosition(include bool) *ActionSequence {
+	if len(s.steps) > 0 {
+		s.steps[len(s.steps)-1].IncludeCursorPosition = &include
+	}
+	return s
+}
+
+// Steps returns a copy of the steps of the sequence.
+func (s *ActionSequence) Steps() []ActionStep {
+	return append([]ActionStep(nil), s.steps...)
+}
+
+// Len returns the number of steps.
+func (s *ActionSequence) Len() int {
+	return len(s.steps)
+}
+
+// RunOptions configures ActionSequence.Run.
+type RunOptions struct {
+	// ContinueOnError runs the remaining steps after a failed one, by default Run stops
+	ContinueOnError bool
+
+	// IncludeScreenShot and IncludeCursorPosition are the defaults of steps that
+	// don't set them, false if nil
+	IncludeScreenShot     *bool
+	IncludeCursorPosition *bool
+}
+
+// ActionStepResult is the outcome of one step of ActionSequence.Run.
+type ActionStepResult struct {
+	Index    int
+	Action   SandboxUseActionDtoActionOneOf
+	Response *SandboxActionResponseDto
+	Err      error
+	// StartedAt and Duration time the request of the step
+	StartedAt time.Time
+	Duration  time.Duration
+}
+
+// Run executes the steps in order with ExecuteSandboxAction.
+//
+//	The returned results cover the steps that were executed. By default Run stops at the
+//	first failed step, with RunOptions.ContinueOnError it runs all steps and joins the errors.
+//	opts can be nil.
+func (s *ActionSequence) Run(ctx context.Context, c Client, sandboxId string, opts *RunOptions) ([]ActionStepResult, error) {
+	var o RunOptions
+	if opts != nil {
+		o = *opts
+	}
+	defaultScreenShot := o.IncludeScreenShot
+	if defaultScreenShot == nil {
+		defaultScreenShot = new(bool)
+	}
+	defaultCursorPosition := o.IncludeCursorPosition
+	if defaultCursorPosition == nil {
+		defaultCursorPosition = new(bool)
+	}
+
+	results := make([]ActionStepResult, 0, len(s.steps))
+	var errs []error
+	for i, step := range s.steps {
+		if err := ctx.Err(); err != nil {
+			return results, errors.Join(append(errs, err)...)
+		}
+
+		dto := ExecuteSandboxActionDto{
+			Action:                step.Action,
+			IncludeScreenShot:     step.IncludeScreenShot,
+			IncludeCursorPosition: step.IncludeCursorPosition,
+		}
+		if dto.IncludeScreenShot == nil {
+			dto.IncludeScreenShot = defaultScreenShot
+		}
+		if dto.IncludeCursorPosition == nil {
+			dto.IncludeCursorPosition = defaultCursorPosition
+		}
+
+		result := ActionStepResult{Index: i, Action: step.Action, StartedAt: time.Now()}
+		result.Response, result.Err = c.ExecuteSandboxAction(ctx, sandboxId, dto)
+		result.Duration = time.Since(result.StartedAt)
+		results = append(results, result)
+
+		if result.Err != nil {
+			loggerOf(c).Warnf("action step %d failed: %v", i, result.Err)
+			errs = append(errs, fmt.Errorf("step %d: %w", i, result.Err))
+			if !o.ContinueOnError {
+				break
+			}
+		}
+	}
+	return results, errors.Join(errs...)
+}
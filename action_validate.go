@@ -0,0 +1,389 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAction is wrapped by the errors returned by the Validate methods of actions.
+var ErrInvalidAction = errors.New("invalid action")
+
+// maxButton is the combination of all mouse button flags: 1|2|4|8|16.
+const maxButton = 31
+
+// FieldError is a problem with one field of an action.
+type FieldError struct {
+	// Field is the JSON name of the field, e.g. "x" or "button"
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ActionValidationError lists the invalid fields of an action.
+type ActionValidationError struct {
+	// Type is the action type, e.g. "mouse:click"
+	Type   string
+	Fields []*FieldError
+}
+
+func (e *ActionValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "invalid %s action", e.Type)
+	for i, f := range e.Fields {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(f.Error())
+	}
+	return sb.String()
+}
+
+func (e *ActionValidationError) Unwrap() error {
+	return ErrInvalidAction
+}
+
+// actionValidator collects the field errors of an action.
+type actionValidator struct {
+	typ    string
+	fields []*FieldError
+}
+
+func (v *actionValidator) add(field, format string, args ...any) {
+	v.fields = append(v.fields, &FieldError{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *actionValidator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ActionValidationError{Type: v.typ, Fields: v.fields}
+}
+
+// length checks a coordinate, relative coordinates may be negative.
+func (v *actionValidator) length(field string, l Length, relative bool) {
+	switch l := l.(type) {
+	case nil:
+		v.add(field, "is required")
+	case *PixelLength:
+		if !relative && l.Value < 0 {
+			v.add(field, "must not be negative, got %d", l.Value)
+		}
+	case *FractionalLength:
+		switch {
+		case l.Denominator == 0:
+			v.add(field, "denominator must not be zero")
+		case l.Denominator < 0:
+			v.add(field, "denominator must be positive, got %d", l.Denominator)
+		case !relative && (l.Numerator < 0 || l.Numerator > l.Denominator):
+			v.add(field, "must be between 0 and 1, got %d/%d", l.Numerator, l.Denominator)
+		case relative && (l.Numerator < -l.Denominator || l.Numerator > l.Denominator):
+			v.add(field, "must be between -1 and 1, got %d/%d", l.Numerator, l.Denominator)
+		}
+	}
+}
+
+// distance checks a length that must be greater than zero.
+func (v *actionValidator) distance(field string, l Length) {
+	switch l := l.(type) {
+	case *PixelLength:
+		if l.Value <= 0 {
+			v.add(field, "must be positive, got %d", l.Value)
+			return
+		}
+	case *FractionalLength:
+		if l.Denominator > 0 && l.Numerator <= 0 {
+			v.add(field, "must be positive, got %d/%d", l.Numerator, l.Denominator)
+			return
+		}
+	}
+	v.length(field, l, false)
+}
+
+func (v *actionValidator) point(x, y Length, relative bool) {
+	v.length("x", x, relative)
+	v.length("y", y, relative)
+}
+
+func (v *actionValidator) button(button int) {
+	if button < 1 || button > maxButton {
+		v.add("button", "must be a combination of 1, 2, 4, 8 and 16, got %d", button)
+	}
+}
+
+func (v *actionValidator) keys(field, keys string) {
+	if _, err := parseXdotoolKeys(keys); err != nil {
+		v.add(field, "%v", err)
+	}
+}
+
+// key checks a single key in xdotool key syntax.
+func (v *actionValidator) key(field, key string) {
+	chords, err := parseXdotoolKeys(key)
+	switch {
+	case err != nil:
+		v.add(field, "%v", err)
+	case len(chords) != 1 || len(chords[0]) != 1:
+		v.add(field, "must be a single key, got %q", key)
+	}
+}
+
+func (v *actionValidator) holdKey(holdKey *string) {
+	if holdKey != nil {
+		v.keys("holdKey", *holdKey)
+	}
+}
+
+func (v *actionValidator) duration(field string, duration int, positive bool) {
+	switch {
+	case positive && duration <= 0:
+		v.add(field, "must be positive, got %d", duration)
+	case duration < 0:
+		v.add(field, "must not be negative, got %d", duration)
+	}
+}
+
+func (v *actionValidator) notEmpty(field, value string) {
+	if value == "" {
+		v.add(field, "must not be empty")
+	}
+}
+
+// Validate checks the fields of the action.
+func (m MouseClickAction) Validate() error {
+	v := actionValidator{typ: "mouse:click"}
+	v.point(m.X, m.Y, m.Relative)
+	v.button(m.Button)
+	v.holdKey(m.HoldKey)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MouseDoubleClickAction) Validate() error {
+	v := actionValidator{typ: "mouse:doubleClick"}
+	v.point(m.X, m.Y, m.Relative)
+	v.button(m.Button)
+	v.holdKey(m.HoldKey)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MouseTripleClickAction) Validate() error {
+	v := actionValidator{typ: "mouse:tripleClick"}
+	v.point(m.X, m.Y, m.Relative)
+	v.button(m.Button)
+	v.holdKey(m.HoldKey)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MouseMoveAction) Validate() error {
+	v := actionValidator{typ: "mouse:move"}
+	v.point(m.X, m.Y, m.Relative)
+	v.holdKey(m.HoldKey)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MouseScrollAction) Validate() error {
+	v := actionValidator{typ: "mouse:scroll"}
+	v.point(m.X, m.Y, m.Relative)
+	if m.StepVertical == 0 && m.StepHorizontal == 0 {
+		v.add("stepVertical", "stepVertical and stepHorizontal must not both be zero")
+	}
+	v.holdKey(m.HoldKey)
+	return v.err()
+}
+
+// Validate checks the fields of the action, Button may be 0 to use the default button.
+func (m MouseDragAction) Validate() error {
+	v := actionValidator{typ: "mouse:drag"}
+	v.length("startX", m.StartX, m.StartRelative)
+	v.length("startY", m.StartY, m.StartRelative)
+	v.length("endX", m.EndX, m.EndRelative)
+	v.length("endY", m.EndY, m.EndRelative)
+	if m.Button != 0 {
+		v.button(m.Button)
+	}
+	v.holdKey(m.HoldKey)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (k KeyboardTypeAction) Validate() error {
+	v := actionValidator{typ: "keyboard:type"}
+	v.notEmpty("content", k.Content)
+	return v.err()
+}
+
+// Validate checks the fields of the action, Keys must be in xdotool key syntax.
+func (k KeyboardHotkeyAction) Validate() error {
+	v := actionValidator{typ: "keyboard:hotkey"}
+	v.keys("keys", k.Keys)
+	if k.Duration != nil {
+		v.duration("duration", *k.Duration, false)
+	}
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (k KeyDownAction) Validate() error {
+	v := actionValidator{typ: "key:down"}
+	v.key("key", k.Key)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (k KeyUpAction) Validate() error {
+	v := actionValidator{typ: "key:up"}
+	v.key("key", k.Key)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (ScreenshotAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (w WaitAction) Validate() error {
+	v := actionValidator{typ: "wait"}
+	v.duration("duration", w.Duration, false)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (FinishedAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (FailedAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (t TouchTapAction) Validate() error {
+	v := actionValidator{typ: "touch:tap"}
+	v.point(t.X, t.Y, false)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (t TouchDragAction) Validate() error {
+	v := actionValidator{typ: "touch:drag"}
+	v.length("startX", t.StartX, false)
+	v.length("startY", t.StartY, false)
+	v.length("endX", t.EndX, false)
+	v.length("endY", t.EndY, false)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (t TouchSwipeAction) Validate() error {
+	v := actionValidator{typ: "touch:swipe"}
+	v.point(t.X, t.Y, false)
+	switch t.Direction {
+	case "up", "down", "left", "right":
+	default:
+		v.add("direction", "must be up, down, left or right, got %q", t.Direction)
+	}
+	v.distance("distance", t.Distance)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (t TouchLongPressAction) Validate() error {
+	v := actionValidator{typ: "touch:longPress"}
+	v.point(t.X, t.Y, false)
+	v.duration("duration", t.Duration, true)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (AndroidHomeAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (AndroidBackAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (m MobileTapAction) Validate() error {
+	v := actionValidator{typ: "mobile:tap"}
+	v.point(m.X, m.Y, false)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MobileDoubleTapAction) Validate() error {
+	v := actionValidator{typ: "mobile:doubleTap"}
+	v.point(m.X, m.Y, false)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MobileSwipeAction) Validate() error {
+	v := actionValidator{typ: "mobile:swipe"}
+	v.length("startX", m.StartX, false)
+	v.length("startY", m.StartY, false)
+	v.length("endX", m.EndX, false)
+	v.length("endY", m.EndY, false)
+	v.duration("duration", m.Duration, true)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MobileTypeAction) Validate() error {
+	v := actionValidator{typ: "mobile:type"}
+	v.notEmpty("content", m.Content)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (m MobileHotkeyAction) Validate() error {
+	v := actionValidator{typ: "mobile:hotkey"}
+	v.notEmpty("key", strings.TrimSpace(m.Key))
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (MobileHomeAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (MobileBackAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (MobileScreenshotAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (m MobileWaitAction) Validate() error {
+	v := actionValidator{typ: "mobile:wait"}
+	v.duration("duration", m.Duration, false)
+	return v.err()
+}
+
+// Validate checks the fields of the action.
+func (MobileFinishedAction) Validate() error { return nil }
+
+// Validate checks the fields of the action.
+func (MobileFailedAction) Validate() error { return nil }
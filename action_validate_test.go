@@ -0,0 +1,87 @@
+package lybic
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestActionValidate(t *testing.T) {
+	px := NewPixelLength(10)
+	tests := []struct {
+		name    string
+		action  SandboxUseActionDtoActionOneOf
+		wantErr bool
+	}{
+		{"click", NewMouseClickAction(px, px, 1), false},
+		{"click fraction", NewMouseClickAction(NewFractionalLength(1, 2), px, 1), false},
+		{"click zero denominator", NewMouseClickAction(NewFractionalLength(1, 0), px, 1), true},
+		{"click negative denominator", NewMouseClickAction(NewFractionalLength(1, -2), px, 1), true},
+		{"click fraction above one", NewMouseClickAction(NewFractionalLength(3, 2), px, 1), true},
+		{"click button zero", NewMouseClickAction(px, px, 0), true},
+		{"click button above 31", NewMouseClickAction(px, px, 32), true},
+		{"click left and right", NewMouseClickAction(px, px, 1|2), false},
+		{"click negative x", NewMouseClickAction(NewPixelLength(-1), px, 1), true},
+		{"drag default button", NewMouseDragAction(px, px, px, px, 0), false},
+		{"drag button above 31", NewMouseDragAction(px, px, px, px, 64), true},
+
+		{"hotkey", NewKeyboardHotkeyAction("ctrl+shift+t"), false},
+		{"hotkey sequence", NewKeyboardHotkeyAction("ctrl+a ctrl+c"), false},
+		{"hotkey empty", NewKeyboardHotkeyAction(""), true},
+		{"hotkey blank", NewKeyboardHotkeyAction("  "), true},
+		{"hotkey empty chord part", NewKeyboardHotkeyAction("ctrl+"), true},
+		{"hotkey spaces split chords", NewKeyboardHotkeyAction("ctrl+page down"), false},
+		{"hotkey punctuation in name", NewKeyboardHotkeyAction("ctrl+pg-dn"), true},
+		{"key down", NewKeyDownAction("shift"), false},
+		{"key down chord", NewKeyDownAction("ctrl+c"), true},
+		{"key up empty", NewKeyUpAction(""), true},
+
+		{"wait", NewWaitAction(0), false},
+		{"wait negative", NewWaitAction(-1), true},
+		{"mobile wait negative", NewMobileWaitAction(-5), true},
+		{"mobile swipe", NewMobileSwipeAction(px, px, px, px, 300), false},
+		{"mobile swipe zero duration", NewMobileSwipeAction(px, px, px, px, 0), true},
+		{"touch swipe", NewTouchSwipeAction(px, px, "up", px), false},
+		{"touch swipe zero distance", NewTouchSwipeAction(px, px, "up", NewPixelLength(0)), true},
+		{"touch swipe direction", NewTouchSwipeAction(px, px, "sideways", px), true},
+		{"long press zero duration", NewTouchLongPressAction(px, px, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.action.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAction) {
+				t.Errorf("Validate() error = %v, want it to wrap ErrInvalidAction", err)
+			}
+		})
+	}
+}
+
+func TestActionValidateFields(t *testing.T) {
+	err := NewMouseClickAction(NewFractionalLength(1, 0), NewPixelLength(-1), 0).Validate()
+	var validationErr *ActionValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Validate() error = %v, want an *ActionValidationError", err)
+	}
+	var fields []string
+	for _, f := range validationErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	if len(fields) != 3 || fields[0] != "x" || fields[1] != "y" || fields[2] != "button" {
+		t.Errorf("fields = %v, want [x y button]", fields)
+	}
+}
+
+func TestXdotoolKeysyms(t *testing.T) {
+	// names outside any table must be left to the sandbox
+	for _, key := range []string{"eacute", "KP_F1", "Select", "Execute", "dead_grave", "Shift_Lock",
+		"XF86AudioMicMute", "XF86Explorer", "0xff0d", "é"} {
+		if err := NewKeyDownAction(key).Validate(); err != nil {
+			t.Errorf("KeyDownAction(%q).Validate() error = %v", key, err)
+		}
+		if err := NewKeyboardHotkeyAction("ctrl+" + key).Validate(); err != nil {
+			t.Errorf("KeyboardHotkeyAction(%q).Validate() error = %v", "ctrl+"+key, err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const defaultBulkConcurrency = 8
+
+// ErrBulkRolledBack marks sandboxes that were created by CreateSandboxes and then
+// deleted again because BulkOptions.CleanupOnFailure was set.
+var ErrBulkRolledBack = errors.New("sandbox deleted after bulk creation failed")
+
+// BulkOptions configures CreateSandboxes, DeleteSandboxes and ExtendSandboxes.
+type BulkOptions struct {
+	// Concurrency limits the number of requests in flight, defaults to 8
+	Concurrency int
+
+	// NameFunc returns the name of the i-th sandbox created by CreateSandboxes.
+	// If nil, every sandbox uses the name from the dto.
+	NameFunc func(i int) string
+
+	// CleanupOnFailure makes CreateSandboxes delete the sandboxes it did create
+	// when at least one creation failed, so that the call is all or nothing.
+	// The results of deleted sandboxes then fail with ErrBulkRolledBack.
+	CleanupOnFailure bool
+}
+
+// BulkResult is the outcome of one item of a bulk operation.
+type BulkResult struct {
+	// Index is the position of the item in the request
+	Index int
+	// SandboxId is the sandbox the item refers to, empty for failed creations
+	// and set for rolled back ones
+	SandboxId string
+	// Sandbox is the created sandbox, only set by CreateSandboxes
+	Sandbox *CreateSandboxResponseDto
+	// Err is the error of the item, nil on success
+	Err error
+}
+
+// BulkError aggregates the failed items of a bulk operation.
+type BulkError struct {
+	Op       string
+	Total    int
+	Failures []BulkResult
+}
+
+func (e *BulkError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d of %d failed", e.Op, len(e.Failures), e.Total)
+	for _, f := range e.Failures {
+		if f.SandboxId != "" {
+			fmt.Fprintf(&sb, "; %s: %v", f.SandboxId, f.Err)
+		} else {
+			fmt.Fprintf(&sb, "; #%d: %v", f.Index, f.Err)
+		}
+	}
+	return sb.String()
+}
+
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// CreateSandboxes creates n sandboxes from the same dto.
+//
+//	The returned results are ordered by index. If any creation fails, the error is a *BulkError.
+func CreateSandboxes(ctx context.Context, c Client, dto CreateSandboxDto, n int, opts *BulkOptions) ([]BulkResult, error) {
+	opts = bulkOptionsOrDefault(opts)
+
+	results := runBulk(ctx, n, nil, opts.Concurrency, func(ctx context.Context, i int) BulkResult {
+		itemDto := dto
+		if opts.NameFunc != nil {
+			itemDto.Name = opts.NameFunc(i)
+		}
+		sandbox, err := c.CreateSandbox(ctx, itemDto)
+		if err != nil {
+			return BulkResult{Index: i, Err: err}
+		}
+		return BulkResult{Index: i, SandboxId: sandbox.Id, Sandbox: sandbox}
+	})
+
+	err := bulkError("create sandboxes", results)
+	if err != nil && opts.CleanupOnFailure {
+		return results, rollbackBulkCreate(ctx, c, "create sandboxes", results, opts)
+	}
+	return results, err
+}
+
+// DeleteSandboxes deletes the sandboxes with the given IDs.
+//
+//	The returned results are ordered like ids. If any deletion fails, the error is a *BulkError.
+func DeleteSandboxes(ctx context.Context, c Client, ids []string, opts *BulkOptions) ([]BulkResult, error) {
+	opts = bulkOptionsOrDefault(opts)
+
+	results := runBulk(ctx, len(ids), ids, opts.Concurrency, func(ctx context.Context, i int) BulkResult {
+		return BulkResult{Index: i, SandboxId: ids[i], Err: c.DeleteSandbox(ctx, ids[i])}
+	})
+	return results, bulkError("delete sandboxes", results)
+}
+
+// ExtendSandboxes extends the sandboxes with the given IDs using the same dto.
+//
+//	The returned results are ordered like ids. If any extension fails, the error is a *BulkError.
+func ExtendSandboxes(ctx context.Context, c Client, ids []string, dto ExtendSandboxDto, opts *BulkOptions) ([]BulkResult, error) {
+	opts = bulkOptionsOrDefault(opts)
+
+	results := runBulk(ctx, len(ids), ids, opts.Concurrency, func(ctx context.Context, i int) BulkResult {
+		return BulkResult{Index: i, SandboxId: ids[i], Err: c.ExtendSandbox(ctx, ids[i], dto)}
+	})
+	return results, bulkError("extend sandboxes", results)
+}
+
+func bulkOptionsOrDefault(opts *BulkOptions) *BulkOptions {
+	var o BulkOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultBulkConcurrency
+	}
+	return &o
+}
+
+// runBulk calls fn for every index in [0, n) with at most concurrency calls in flight.
+// Items not started before ctx is done fail with the context error, and keep their
+// sandbox ID when ids is not nil.
+func runBulk(ctx context.Context, n int, ids []string, concurrency int, fn func(ctx context.Context, i int) BulkResult) []BulkResult {
+	results := make([]BulkResult, n)
+	for i := range results {
+		results[i].Index = i
+		if ids != nil {
+			results[i].SandboxId = ids[i]
+		}
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = fn(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// rollbackBulkCreate deletes the sandboxes created by a failed bulk creation, marks
+// their results with ErrBulkRolledBack and returns the updated error. Sandboxes that
+// could not be deleted keep their successful result.
+func rollbackBulkCreate(ctx context.Context, c Client, op string, results []BulkResult, opts *BulkOptions) error {
+	var created []string
+	var createdIndex []int
+	for i, r := range results {
+		if r.Err == nil {
+			created = append(created, r.SandboxId)
+			createdIndex = append(createdIndex, i)
+		}
+	}
+	loggerOf(c).Warnf("%s failed, deleting %d created sandboxes", op, len(created))
+	deleted, err := DeleteSandboxes(context.WithoutCancel(ctx), c, created, opts)
+	if err != nil {
+		loggerOf(c).Errorf("failed to clean up after %s: %v", op, err)
+	}
+	for j, d := range deleted {
+		if d.Err == nil {
+			results[createdIndex[j]].Err = ErrBulkRolledBack
+		}
+	}
+	return bulkError(op, results)
+}
+
+func bulkError(op string, results []BulkResult) error {
+	var failures []BulkResult
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, r)
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &BulkError{Op: op, Total: len(results), Failures: failures}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrMachineImageNotReady = errors.New("machine image is not ready")
+
+// ErrMachineImageQuotaExceeded is returned when the organization has no machine image
+// quota left, as reported by MachineImagesResponseDtoQuota.
+type ErrMachineImageQuotaExceeded struct {
+	Used  int32
+	Limit int32
+	// Err is the error returned by the API, nil if the quota was exhausted before the call
+	Err error
+}
+
+func (e *ErrMachineImageQuotaExceeded) Error() string {
+	msg := fmt.Sprintf("machine image quota exceeded: %d of %d images used", e.Used, e.Limit)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ErrMachineImageQuotaExceeded) Unwrap() error {
+	return e.Err
+}
+
+// RestoreOptions configures Restore and Fork.
+type RestoreOptions struct {
+	BulkOptions
+
+	// MaxLifeSeconds is the life time of every restored sandbox, defaults to 3600
+	MaxLifeSeconds int32
+
+	// ProjectId is the project of the restored sandboxes, nil uses the default project
+	ProjectId *string
+}
+
+// Checkpoint creates a machine image of the sandbox and waits until it is READY.
+//
+//	If the organization is out of machine image quota, the error is an
+//	*ErrMachineImageQuotaExceeded.
+func Checkpoint(ctx context.Context, c Client, sandboxId string, name string) (*MachineImageResponseDto, error) {
+	if _, err := PreflightMachineImageQuota(ctx, c, nil); err != nil {
+		return nil, err
+	}
+
+	image, err := c.CreateMachineImage(ctx, CreateMachineImageDto{SandboxId: sandboxId, Name: name})
+	if err != nil {
+		// the quota may have been used up concurrently, report it if so
+		if images, listErr := c.ListMachineImages(ctx, "org"); listErr == nil {
+			if quota := images.Quota; quota.Limit > 0 && quota.Used >= quota.Limit {
+				return nil, &ErrMachineImageQuotaExceeded{Used: quota.Used, Limit: quota.Limit, Err: err}
+			}
+		}
+		return nil, err
+	}
+
+	return WaitForMachineImage(ctx, c, image.Id)
+}
+
+// Restore creates n sandboxes from a READY machine image.
+//
+//	Sandboxes are named "<image name>-<i>" unless opts.NameFunc is set. The returned
+//	results are ordered by index. If any creation fails, the error is a *BulkError.
+func Restore(ctx context.Context, c Client, image *MachineImageResponseDto, n int, opts *RestoreOptions) ([]BulkResult, error) {
+	if image.GetStatus() != MachineImageReady {
+		return nil, fmt.Errorf("%w: %s is %s", ErrMachineImageNotReady, image.Id, image.Status)
+	}
+
+	var o RestoreOptions
+	if opts != nil {
+		o = *opts
+	}
+	bulk := bulkOptionsOrDefault(&o.BulkOptions)
+
+	results := runBulk(ctx, n, nil, bulk.Concurrency, func(ctx context.Context, i int) BulkResult {
+		dto := CreateSandboxFromImageDto{
+			ImageId:        image.Id,
+			Name:           fmt.Sprintf("%s-%d", image.Name, i),
+			MaxLifeSeconds: o.MaxLifeSeconds,
+			ProjectId:      o.ProjectId,
+		}
+		if bulk.NameFunc != nil {
+			dto.Name = bulk.NameFunc(i)
+		}
+		resp, err := c.CreateSandboxFromImage(ctx, dto)
+		if err != nil {
+			return BulkResult{Index: i, Err: err}
+		}
+		return BulkResult{Index: i, SandboxId: resp.Sandbox.Id, Sandbox: &resp.Sandbox}
+	})
+
+	err := bulkError("restore sandboxes", results)
+	if err != nil && bulk.CleanupOnFailure {
+		return results, rollbackBulkCreate(ctx, c, "restore sandboxes", results, bulk)
+	}
+	return results, err
+}
+
+// Fork checkpoints the sandbox and restores n sandboxes from the checkpoint.
+//
+//	The checkpoint image is returned even when restoring fails, so that it can be
+//	reused or deleted by the caller.
+func Fork(ctx context.Context, c Client, sandboxId string, n int, opts *RestoreOptions) (*MachineImageResponseDto, []BulkResult, error) {
+	name := fmt.Sprintf("fork-%s-%d", sandboxId, time.Now().Unix())
+	image, err := Checkpoint(ctx, c, sandboxId, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := Restore(ctx, c, image, n, opts)
+	return image, results, err
+}
@@ -123,13 +123,17 @@ func newClient(config *Config) (*client, error) {
 		}
 	}
 
-	return &client{
+	c := &client{
 		client: &http.Client{
 			Timeout:   time.Duration(config.Timeout) * time.Second,
 			Transport: transport,
 		},
 		config: config,
-	}, nil
+	}
+	if config.Tracker != nil {
+		config.Tracker.bind(c)
+	}
+	return c, nil
 }
 
 func (c *client) request(ctx context.Context, method, url string, params map[string]string, bodyDto any) (*http.Response, error) {
@@ -0,0 +1,31 @@
+module github.com/lybic/lybic-sdk-go/cmd/lybic-exporter
+
+go 1.23.0
+
+require (
+	github.com/lybic/lybic-sdk-go v0.0.0-00010101000000-000000000000
+	github.com/lybic/lybic-sdk-go/pkg/collector v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.22.0
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/google/jsonschema-go v0.4.2 // indirect
+	github.com/modelcontextprotocol/go-sdk v1.3.1 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.62.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/segmentio/asm v1.1.3 // indirect
+	github.com/segmentio/encoding v0.5.3 // indirect
+	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	golang.org/x/oauth2 v0.30.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
+replace (
+	github.com/lybic/lybic-sdk-go => ../..
+	github.com/lybic/lybic-sdk-go/pkg/collector => ../../pkg/collector
+)
@@ -0,0 +1,81 @@
+// Command lybic-exporter serves the resources of a Lybic organization as Prometheus metrics.
+//
+//	The organization and API key are read from the LYBIC_ORG_ID, LYBIC_API_KEY and
+//	LYBIC_API_ENDPOINT environment variables, like lybic.NewConfig does.
+//
+//	lybic-exporter -listen :9464 -interval 1m -mcp
+//
+//	The command and pkg/collector are separate modules, so that SDK users do not depend
+//	on the Prometheus client. Build it from a checkout of the repository:
+//
+//	cd cmd/lybic-exporter && go build
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go"
+	"github.com/lybic/lybic-sdk-go/pkg/collector"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run holds the exporter, so that its deferred cleanups run before main exits.
+func run() error {
+	listen := flag.String("listen", ":9464", "address to serve /metrics on")
+	interval := flag.Duration("interval", time.Minute, "time between two refreshes")
+	withMcp := flag.Bool("mcp", false, "also export MCP servers")
+	sandboxExpiry := flag.Bool("sandbox-expiry", false, "export the expiry of every sandbox, one series per sandbox")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	client, err := lybic.NewClient(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	opts := &collector.Options{Interval: *interval, SandboxExpiry: *sandboxExpiry}
+	if *withMcp {
+		mcp, err := lybic.NewMcpClient(ctx, lybic.McpOption{UsingClient: client})
+		if err != nil {
+			return fmt.Errorf("failed to create MCP client: %w", err)
+		}
+		defer mcp.Close()
+		opts.Mcp = mcp
+	}
+
+	c := collector.New(client, opts)
+	go c.Run(ctx)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: *listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	log.Printf("serving metrics on %s/metrics", *listen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -46,6 +46,10 @@ import (
 //
 // # KeyboardHotkeyAction
 //
+// # KeyDownAction
+//
+// # KeyUpAction
+//
 // # ScreenshotAction
 //
 // # WaitAction
@@ -57,6 +61,9 @@ type ComputerUseActionDtoActionOneOf interface {
 	MarshalJSON() ([]byte, error)
 	UnmarshalJSON(src []byte) error
 
+	// Validate checks the fields of the action before it is sent
+	Validate() error
+
 	// _internalComputerUseActionDtoActionOneOf is a dummy method to prevent external implementations
 	_internalComputerUseActionDtoActionOneOf()
 	// _internalSandboxUseActionDtoActionOneOf is a dummy method to prevent external implementations
@@ -256,6 +263,35 @@ func NewKeyboardHotkeyAction(keys string) *KeyboardHotkeyAction {
 	}
 }
 
+// KeyDownAction presses ONE key down, in xdotool key syntax. Pair it with a KeyUpAction,
+// see HoldKeys.
+type KeyDownAction struct {
+	Type   string  `json:"type"` // set to key:down
+	Key    string  `json:"key"`
+	CallId *string `json:"callId,omitempty"`
+}
+
+func NewKeyDownAction(key string) *KeyDownAction {
+	return &KeyDownAction{
+		Type: "key:down",
+		Key:  key,
+	}
+}
+
+// KeyUpAction releases ONE key previously pressed by a KeyDownAction.
+type KeyUpAction struct {
+	Type   string  `json:"type"` // set to key:up
+	Key    string  `json:"key"`
+	CallId *string `json:"callId,omitempty"`
+}
+
+func NewKeyUpAction(key string) *KeyUpAction {
+	return &KeyUpAction{
+		Type: "key:up",
+		Key:  key,
+	}
+}
+
 type ScreenshotAction struct {
 	Type   string  `json:"type"` // set to screenshot
 	CallId *string `json:"callId,omitempty"`
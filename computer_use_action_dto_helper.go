@@ -390,6 +390,9 @@ func (m MouseDragAction) MarshalJSON() ([]byte, error) {
 		"startRelative": m.StartRelative,
 		"endRelative":   m.EndRelative,
 	}
+	if m.Button != 0 {
+		toSerialize["button"] = m.Button
+	}
 	if m.HoldKey != nil {
 		toSerialize["holdKey"] = *m.HoldKey
 	}
@@ -441,6 +444,9 @@ func (m *MouseDragAction) UnmarshalJSON(src []byte) error {
 	if v, ok := value["endRelative"].(bool); ok {
 		m.EndRelative = v
 	}
+	if v, ok := value["button"].(float64); ok {
+		m.Button = int(v)
+	}
 	if v, ok := value["holdKey"].(string); ok {
 		m.HoldKey = &v
 	}
@@ -525,6 +531,68 @@ func (k *KeyboardHotkeyAction) UnmarshalJSON(src []byte) error {
 
 func (KeyboardHotkeyAction) _internalComputerUseActionDtoActionOneOf() {}
 
+func (k KeyDownAction) MarshalJSON() ([]byte, error) {
+	var toSerialize map[string]interface{}
+	toSerialize = map[string]interface{}{
+		"type": "key:down",
+		"key":  k.Key,
+	}
+	if k.CallId != nil {
+		toSerialize["callId"] = *k.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (k *KeyDownAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		k.Type = v
+	}
+	if v, ok := value["key"].(string); ok {
+		k.Key = v
+	}
+	if v, ok := value["callId"].(string); ok {
+		k.CallId = &v
+	}
+	return nil
+}
+
+func (KeyDownAction) _internalComputerUseActionDtoActionOneOf() {}
+
+func (k KeyUpAction) MarshalJSON() ([]byte, error) {
+	var toSerialize map[string]interface{}
+	toSerialize = map[string]interface{}{
+		"type": "key:up",
+		"key":  k.Key,
+	}
+	if k.CallId != nil {
+		toSerialize["callId"] = *k.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (k *KeyUpAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		k.Type = v
+	}
+	if v, ok := value["key"].(string); ok {
+		k.Key = v
+	}
+	if v, ok := value["callId"].(string); ok {
+		k.CallId = &v
+	}
+	return nil
+}
+
+func (KeyUpAction) _internalComputerUseActionDtoActionOneOf() {}
+
 func (s ScreenshotAction) MarshalJSON() ([]byte, error) {
 	var toSerialize map[string]interface{}
 	toSerialize = map[string]interface{}{
@@ -675,6 +743,10 @@ func rawMessageToComputerUseActionDtoActionOneOf(rawAction json.RawMessage) (Com
 		action = &KeyboardTypeAction{}
 	case "keyboard:hotkey":
 		action = &KeyboardHotkeyAction{}
+	case "key:down":
+		action = &KeyDownAction{}
+	case "key:up":
+		action = &KeyUpAction{}
 	case "screenshot":
 		action = &ScreenshotAction{}
 	case "wait":
@@ -701,7 +773,39 @@ func (MouseScrollAction) _internalSandboxUseActionDtoActionOneOf()      {}
 func (MouseDragAction) _internalSandboxUseActionDtoActionOneOf()        {}
 func (KeyboardTypeAction) _internalSandboxUseActionDtoActionOneOf()     {}
 func (KeyboardHotkeyAction) _internalSandboxUseActionDtoActionOneOf()   {}
+func (KeyDownAction) _internalSandboxUseActionDtoActionOneOf()          {}
+func (KeyUpAction) _internalSandboxUseActionDtoActionOneOf()            {}
 func (FinishedAction) _internalSandboxUseActionDtoActionOneOf()         {}
 func (ScreenshotAction) _internalSandboxUseActionDtoActionOneOf()       {}
 func (WaitAction) _internalSandboxUseActionDtoActionOneOf()             {}
 func (FailedAction) _internalSandboxUseActionDtoActionOneOf()           {}
+
+// UnmarshalJSON decodes the actions by their type. It lives outside the generated model,
+// which can not decode the action union.
+func (o *ComputerUseActionResponseDto) UnmarshalJSON(data []byte) error {
+	// Use a temporary struct with Actions as a slice of json.RawMessage to avoid recursion.
+	var temp struct {
+		Actions  []json.RawMessage `json:"actions"`
+		Unknown  *string           `json:"unknown,omitempty"`
+		Memory   *string           `json:"memory,omitempty"`
+		Thoughts *string           `json:"thoughts,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	o.Unknown = temp.Unknown
+	o.Memory = temp.Memory
+	o.Thoughts = temp.Thoughts
+	o.Actions = make([]ComputerUseActionDtoActionOneOf, len(temp.Actions))
+
+	for i, rawAction := range temp.Actions {
+		action, err := rawMessageToComputerUseActionDtoActionOneOf(rawAction)
+		if err != nil {
+			return fmt.Errorf("error unmarshaling action at index %d: %w", i, err)
+		}
+		o.Actions[i] = action
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultShapePrice is the multiplier assumed for shapes whose price is unknown.
+const defaultShapePrice = 1.0
+
+// ErrShapePriceUnknown is returned by a budget guarded client when it is asked to
+// create a sandbox of a shape whose price it has not seen yet.
+// Set it with CostEstimator.SetShapePrice.
+var ErrShapePriceUnknown = errors.New("shape price is unknown")
+
+// CostTotal is the aggregated spend of a group of sandboxes.
+type CostTotal struct {
+	Sandboxes int
+	// Accrued is the spend from creation until now
+	Accrued float64
+	// Projected is the spend from creation until expiry
+	Projected float64
+}
+
+func (t *CostTotal) add(c SandboxCost) {
+	t.Sandboxes++
+	t.Accrued += c.Accrued
+	t.Projected += c.Projected
+}
+
+// SandboxCost is the estimated spend of a single sandbox.
+type SandboxCost struct {
+	SandboxId string
+	Name      string
+	ProjectId string
+	ShapeName string
+	Status    SandboxStatus
+	// PricePerHour is the billing multiplier of the shape
+	PricePerHour float64
+	// Elapsed is the time since the sandbox was created
+	Elapsed time.Duration
+	// Remaining is the time until the sandbox expires, zero once stopped or expired
+	Remaining time.Duration
+	Accrued   float64
+	Projected float64
+}
+
+// CostEstimate is the spend of the organization at a point in time, grouped by project and shape.
+type CostEstimate struct {
+	At        time.Time
+	Sandboxes []SandboxCost
+	ByProject map[string]CostTotal
+	ByShape   map[string]CostTotal
+	Total     CostTotal
+}
+
+// CostEstimator estimates sandbox spend from the shape price multiplier and the sandbox lifetime.
+//
+//	Spend is expressed in billed hours multiplied by HourlyRate, so with the default rate
+//	of 1 the figures are billed hours. Shape prices are read through GetSandbox and cached.
+type CostEstimator struct {
+	client Client
+
+	// HourlyRate converts billed hours into a currency, defaults to 1
+	HourlyRate float64
+
+	mu     sync.Mutex
+	prices map[string]float64
+}
+
+// NewCostEstimator creates a CostEstimator. A zero hourlyRate defaults to 1.
+func NewCostEstimator(client Client, hourlyRate float64) *CostEstimator {
+	if hourlyRate <= 0 {
+		hourlyRate = 1
+	}
+	return &CostEstimator{
+		client:     client,
+		HourlyRate: hourlyRate,
+		prices:     make(map[string]float64),
+	}
+}
+
+// SetShapePrice sets the price multiplier of a shape, e.g. for shapes that have no
+// sandbox yet and therefore cannot be looked up.
+func (e *CostEstimator) SetShapePrice(shapeName string, pricePerHour float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.prices[shapeName] = pricePerHour
+}
+
+// knownShapePrice returns the cached price of a shape, or ErrShapePriceUnknown.
+func (e *CostEstimator) knownShapePrice(shapeName string) (float64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	price, ok := e.prices[shapeName]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrShapePriceUnknown, shapeName)
+	}
+	return price, nil
+}
+
+// shapePrice returns the cached price of a shape, looking it up through the given
+// sandbox when it is not cached yet. sandboxId may be empty.
+func (e *CostEstimator) shapePrice(ctx context.Context, shapeName, sandboxId string) float64 {
+	e.mu.Lock()
+	price, ok := e.prices[shapeName]
+	e.mu.Unlock()
+	if ok {
+		return price
+	}
+	if sandboxId == "" {
+		loggerOf(e.client).Warnf("price of shape %s is unknown, assuming %v", shapeName, defaultShapePrice)
+		return defaultShapePrice
+	}
+
+	sandbox, err := e.client.GetSandbox(ctx, sandboxId)
+	if err != nil {
+		loggerOf(e.client).Warnf("failed to look up price of shape %s, assuming %v: %v", shapeName, defaultShapePrice, err)
+		return defaultShapePrice
+	}
+	price = float64(sandbox.Sandbox.Shape.PricePerHour)
+	e.SetShapePrice(shapeName, price)
+	return price
+}
+
+// SandboxCost estimates the spend of a sandbox at time now.
+func (e *CostEstimator) SandboxCost(ctx context.Context, sandbox CreateSandboxResponseDto, now time.Time) SandboxCost {
+	price := e.shapePrice(ctx, sandbox.ShapeName, sandbox.Id)
+	cost := SandboxCost{
+		SandboxId:    sandbox.Id,
+		Name:         sandbox.Name,
+		ProjectId:    sandbox.ProjectId,
+		ShapeName:    sandbox.ShapeName,
+		Status:       sandbox.GetStatus(),
+		PricePerHour: price,
+		Elapsed:      max(now.Sub(sandbox.CreatedAt), 0),
+	}
+	if cost.Status != SandboxStopped && cost.Status != SandboxError {
+		cost.Remaining = max(sandbox.ExpiresAt.Sub(now), 0)
+	}
+	cost.Accrued = e.spend(price, cost.Elapsed)
+	cost.Projected = e.spend(price, cost.Elapsed+cost.Remaining)
+	return cost
+}
+
+// Estimate lists the sandboxes of the organization and estimates their spend.
+func (e *CostEstimator) Estimate(ctx context.Context) (*CostEstimate, error) {
+	sandboxes, err := e.client.ListSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &CostEstimate{
+		At:        time.Now(),
+		ByProject: make(map[string]CostTotal),
+		ByShape:   make(map[string]CostTotal),
+	}
+	for _, sandbox := range sandboxes {
+		cost := e.SandboxCost(ctx, sandbox, estimate.At)
+		estimate.Sandboxes = append(estimate.Sandboxes, cost)
+
+		project := estimate.ByProject[cost.ProjectId]
+		project.add(cost)
+		estimate.ByProject[cost.ProjectId] = project
+
+		shape := estimate.ByShape[cost.ShapeName]
+		shape.add(cost)
+		estimate.ByShape[cost.ShapeName] = shape
+
+		estimate.Total.add(cost)
+	}
+	return estimate, nil
+}
+
+func (e *CostEstimator) spend(pricePerHour float64, d time.Duration) float64 {
+	return pricePerHour * d.Hours() * e.HourlyRate
+}
+
+// Budget holds spend limits in the unit of the CostEstimator. A zero limit is unlimited.
+type Budget struct {
+	// Total limits the projected spend of the whole organization
+	Total float64
+	// PerProject limits the projected spend per project ID
+	PerProject map[string]float64
+	// PerShape limits the projected spend per shape name
+	PerShape map[string]float64
+}
+
+// ErrBudgetExceeded is returned by a budget guarded client when a call would push the
+// projected spend past a limit.
+type ErrBudgetExceeded struct {
+	// Scope is "total", "project" or "shape"
+	Scope string
+	// Key is the project ID or shape name, empty for the total scope
+	Key string
+	// Limit is the configured limit
+	Limit float64
+	// Projected is the projected spend including the refused call
+	Projected float64
+}
+
+func (e *ErrBudgetExceeded) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("budget exceeded: projected %s spend %.2f would exceed limit %.2f", e.Scope, e.Projected, e.Limit)
+	}
+	return fmt.Sprintf("budget exceeded: projected spend %.2f of %s %s would exceed limit %.2f", e.Projected, e.Scope, e.Key, e.Limit)
+}
+
+// budgetGuard is a Client that refuses calls that would exceed a Budget.
+type budgetGuard struct {
+	Client
+	estimator *CostEstimator
+	budget    Budget
+
+	// mu serializes guarded calls so that two concurrent calls cannot both pass the check
+	mu               sync.Mutex
+	defaultProjectId string
+}
+
+// NewBudgetGuard wraps a client so that CreateSandbox, CreateSandboxFromImage and
+// ExtendSandbox fail with *ErrBudgetExceeded instead of pushing the projected spend,
+// as computed by the estimator, past the budget. All other calls pass through.
+//
+//	The price of a new sandbox is taken from the running sandboxes of the same shape.
+//	Creating a sandbox of a shape without running sandboxes fails with
+//	ErrShapePriceUnknown unless the price was set with CostEstimator.SetShapePrice.
+//	Guarded calls are serialized, which limits the throughput of bulk creation.
+func NewBudgetGuard(client Client, estimator *CostEstimator, budget Budget) Client {
+	return &budgetGuard{
+		Client:    client,
+		estimator: estimator,
+		budget:    budget,
+	}
+}
+
+func (g *budgetGuard) CreateSandbox(ctx context.Context, dto CreateSandboxDto) (*CreateSandboxResponseDto, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	lifetime := time.Duration(dto.MaxLifeSeconds) * time.Second
+	if lifetime <= 0 {
+		lifetime = time.Hour
+	}
+	if err := g.checkCreate(ctx, dto.ProjectId, dto.Shape, lifetime); err != nil {
+		return nil, err
+	}
+	return g.Client.CreateSandbox(ctx, dto)
+}
+
+func (g *budgetGuard) CreateSandboxFromImage(ctx context.Context, dto CreateSandboxFromImageDto) (*CreateSandboxFromImageResponseDto, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	shapeName, err := g.imageShape(ctx, dto.ImageId)
+	if err != nil {
+		return nil, err
+	}
+	lifetime := time.Duration(dto.MaxLifeSeconds) * time.Second
+	if lifetime <= 0 {
+		lifetime = time.Hour
+	}
+	projectId := ""
+	if dto.ProjectId != nil {
+		projectId = *dto.ProjectId
+	}
+	if err := g.checkCreate(ctx, projectId, shapeName, lifetime); err != nil {
+		return nil, err
+	}
+	return g.Client.CreateSandboxFromImage(ctx, dto)
+}
+
+func (g *budgetGuard) ExtendSandbox(ctx context.Context, sandboxId string, dto ExtendSandboxDto) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sandbox, err := g.Client.GetSandbox(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+	price := float64(sandbox.Sandbox.Shape.PricePerHour)
+	g.estimator.SetShapePrice(sandbox.Sandbox.ShapeName, price)
+
+	remaining := max(time.Until(sandbox.Sandbox.ExpiresAt), 0)
+	extension := max(time.Duration(dto.MaxLifeSeconds)*time.Second-remaining, 0)
+	estimate, err := g.estimator.Estimate(ctx)
+	if err != nil {
+		return err
+	}
+	if err := g.check(ctx, estimate, sandbox.Sandbox.ProjectId, sandbox.Sandbox.ShapeName, g.estimator.spend(price, extension)); err != nil {
+		return err
+	}
+	return g.Client.ExtendSandbox(ctx, sandboxId, dto)
+}
+
+// checkCreate checks a new sandbox of the shape running for lifetime. The estimate
+// runs first, since it fills the price cache from the running sandboxes.
+func (g *budgetGuard) checkCreate(ctx context.Context, projectId, shapeName string, lifetime time.Duration) error {
+	estimate, err := g.estimator.Estimate(ctx)
+	if err != nil {
+		return err
+	}
+	price, err := g.estimator.knownShapePrice(shapeName)
+	if err != nil {
+		return err
+	}
+	return g.check(ctx, estimate, projectId, shapeName, g.estimator.spend(price, lifetime))
+}
+
+// check compares the projected spend of the estimate plus additional against every limit.
+func (g *budgetGuard) check(ctx context.Context, estimate *CostEstimate, projectId, shapeName string, additional float64) error {
+	if limit := g.budget.Total; limit > 0 {
+		if projected := estimate.Total.Projected + additional; projected > limit {
+			return &ErrBudgetExceeded{Scope: "total", Limit: limit, Projected: projected}
+		}
+	}
+	if limit := g.budget.PerShape[shapeName]; limit > 0 {
+		if projected := estimate.ByShape[shapeName].Projected + additional; projected > limit {
+			return &ErrBudgetExceeded{Scope: "shape", Key: shapeName, Limit: limit, Projected: projected}
+		}
+	}
+	if len(g.budget.PerProject) > 0 {
+		if projectId == "" {
+			id, err := g.defaultProject(ctx)
+			if err != nil {
+				return err
+			}
+			projectId = id
+		}
+		if limit := g.budget.PerProject[projectId]; limit > 0 {
+			if projected := estimate.ByProject[projectId].Projected + additional; projected > limit {
+				return &ErrBudgetExceeded{Scope: "project", Key: projectId, Limit: limit, Projected: projected}
+			}
+		}
+	}
+	return nil
+}
+
+// defaultProject resolves the project used by create calls that do not set one.
+func (g *budgetGuard) defaultProject(ctx context.Context) (string, error) {
+	if g.defaultProjectId != "" {
+		return g.defaultProjectId, nil
+	}
+	projects, err := g.Client.ListProjects(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, project := range projects {
+		if project.DefaultProject {
+			g.defaultProjectId = project.Id
+			return project.Id, nil
+		}
+	}
+	return "", nil
+}
+
+func (g *budgetGuard) imageShape(ctx context.Context, imageId string) (string, error) {
+	images, err := g.Client.ListMachineImages(ctx, "all")
+	if err != nil {
+		return "", err
+	}
+	for _, image := range images.Images {
+		if image.Id == imageId {
+			return image.ShapeName, nil
+		}
+	}
+	return "", fmt.Errorf("machine image %s not found", imageId)
+}
@@ -0,0 +1,617 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// PlannedOperation is a mutating call recorded by a dry-run client instead of being sent.
+type PlannedOperation struct {
+	// Seq is the position of the operation in the plan, starting at 1
+	Seq  int       `json:"seq"`
+	Time time.Time `json:"time"`
+	// Op is the name of the client method, e.g. "CreateSandbox"
+	Op string `json:"op"`
+	// Target is the sandbox, image, project or MCP server the operation applies to
+	Target string `json:"target,omitempty"`
+	// Request is the dto passed to the method
+	Request any `json:"request,omitempty"`
+	// Response is the synthetic response returned to the caller
+	Response any `json:"response,omitempty"`
+}
+
+// DryRunPlan collects the operations of dry-run clients, it is safe for concurrent use.
+type DryRunPlan struct {
+	mu         sync.Mutex
+	operations []PlannedOperation
+	nextId     int
+}
+
+// NewDryRunPlan creates an empty plan.
+func NewDryRunPlan() *DryRunPlan {
+	return &DryRunPlan{}
+}
+
+// Operations returns a copy of the planned operations in call order.
+func (p *DryRunPlan) Operations() []PlannedOperation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.operations)
+}
+
+// MarshalJSON encodes the plan as {"operations": [...]}.
+func (p *DryRunPlan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Operations []PlannedOperation `json:"operations"`
+	}{p.Operations()})
+}
+
+// WriteJSON writes the plan as indented JSON.
+func (p *DryRunPlan) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func (p *DryRunPlan) record(logger Logger, op, target string, request, response any) {
+	p.mu.Lock()
+	seq := len(p.operations) + 1
+	p.operations = append(p.operations, PlannedOperation{
+		Seq:      seq,
+		Time:     time.Now(),
+		Op:       op,
+		Target:   target,
+		Request:  request,
+		Response: response,
+	})
+	p.mu.Unlock()
+	logger.Infof("dry-run: planned #%d %s %s", seq, op, target)
+}
+
+// syntheticId returns a unique ID that can not clash with real resource IDs.
+func (p *DryRunPlan) syntheticId(kind string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextId++
+	return fmt.Sprintf("dryrun-%s-%d", kind, p.nextId)
+}
+
+// dryRunClient is a Client that records mutating calls in a plan instead of sending them.
+type dryRunClient struct {
+	Client
+	plan   *DryRunPlan
+	logger Logger
+
+	mu               sync.Mutex
+	sandboxes        map[string]CreateSandboxResponseDto
+	deletedSandboxes map[string]bool
+	images           map[string]MachineImageResponseDto
+	deletedImages    map[string]bool
+	// mappings holds the HTTP port mappings created in dry-run, by sandbox ID
+	mappings        map[string][]HttpMappingResponseDto
+	deletedMappings map[mappingKey]bool
+}
+
+// mappingKey identifies an HTTP port mapping of a sandbox.
+type mappingKey struct {
+	sandboxId      string
+	targetEndpoint string
+}
+
+// NewDryRunClient wraps a client so that read calls go to the API while mutating calls
+// are recorded in the plan and answered with synthetic, well-formed responses.
+//
+//	Sandboxes, machine images and HTTP port mappings created in dry-run are remembered:
+//	they show up in list calls and can be read until they are deleted, and reads of
+//	sandboxes created in dry-run are answered from the plan without calling the API.
+//	Existing sandboxes, machine images and HTTP port mappings deleted in dry-run disappear
+//	from list calls, and reading them returns a not found Error, like after a real delete.
+//	This way scripts which create and then inspect resources can be planned end to end.
+//	If plan is nil, a new plan is used and the operations are only logged.
+func NewDryRunClient(client Client, plan *DryRunPlan) Client {
+	if plan == nil {
+		plan = NewDryRunPlan()
+	}
+	return &dryRunClient{
+		Client:           client,
+		plan:             plan,
+		logger:           loggerOf(client),
+		sandboxes:        make(map[string]CreateSandboxResponseDto),
+		deletedSandboxes: make(map[string]bool),
+		images:           make(map[string]MachineImageResponseDto),
+		deletedImages:    make(map[string]bool),
+		mappings:         make(map[string][]HttpMappingResponseDto),
+		deletedMappings:  make(map[mappingKey]bool),
+	}
+}
+
+// notFound is the error the API returns for a resource that does not exist.
+func notFound(kind, id string) error {
+	return Error{
+		Code:       strconv.Itoa(http.StatusNotFound),
+		Message:    fmt.Sprintf("%s %s not found", kind, id),
+		StatusCode: http.StatusNotFound,
+	}
+}
+
+func (d *dryRunClient) syntheticSandbox(name, projectId, shapeName string, maxLifeSeconds float64) CreateSandboxResponseDto {
+	if maxLifeSeconds <= 0 {
+		maxLifeSeconds = 3600
+	}
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(maxLifeSeconds * float64(time.Second)))
+	status := string(SandboxRunning)
+	sandbox := CreateSandboxResponseDto{
+		Id:        d.plan.syntheticId("sandbox"),
+		Name:      name,
+		ExpiredAt: expiresAt,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		ProjectId: projectId,
+		ShapeName: shapeName,
+		Status:    &status,
+	}
+	d.mu.Lock()
+	d.sandboxes[sandbox.Id] = sandbox
+	d.mu.Unlock()
+	return sandbox
+}
+
+func (d *dryRunClient) synthetic(sandboxId string) (CreateSandboxResponseDto, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sandbox, ok := d.sandboxes[sandboxId]
+	return sandbox, ok
+}
+
+// deleted reports whether an existing sandbox was deleted in dry-run.
+func (d *dryRunClient) deleted(sandboxId string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deletedSandboxes[sandboxId]
+}
+
+func (d *dryRunClient) ListSandboxes(ctx context.Context) ([]CreateSandboxResponseDto, error) {
+	sandboxes, err := d.Client.ListSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sandboxes = slices.DeleteFunc(sandboxes, func(sandbox CreateSandboxResponseDto) bool {
+		return d.deletedSandboxes[sandbox.Id]
+	})
+	for _, sandbox := range d.sandboxes {
+		sandboxes = append(sandboxes, sandbox)
+	}
+	return sandboxes, nil
+}
+
+func (d *dryRunClient) GetSandbox(ctx context.Context, sandboxId string) (*GetSandboxResponseDto, error) {
+	if d.deleted(sandboxId) {
+		return nil, notFound("sandbox", sandboxId)
+	}
+	sandbox, ok := d.synthetic(sandboxId)
+	if !ok {
+		return d.Client.GetSandbox(ctx, sandboxId)
+	}
+	return &GetSandboxResponseDto{Sandbox: GetSandboxResponseDtoSandbox{
+		Id:        sandbox.Id,
+		Name:      sandbox.Name,
+		ExpiredAt: sandbox.ExpiredAt,
+		ExpiresAt: sandbox.ExpiresAt,
+		CreatedAt: sandbox.CreatedAt,
+		ProjectId: sandbox.ProjectId,
+		ShapeName: sandbox.ShapeName,
+		Status:    sandbox.Status,
+		Shape:     GetSandboxResponseDtoSandboxShape{Name: sandbox.ShapeName},
+	}}, nil
+}
+
+func (d *dryRunClient) PreviewSandbox(ctx context.Context, sandboxId string) (*SandboxActionResponseDto, error) {
+	if d.deleted(sandboxId) {
+		return nil, notFound("sandbox", sandboxId)
+	}
+	if _, ok := d.synthetic(sandboxId); !ok {
+		return d.Client.PreviewSandbox(ctx, sandboxId)
+	}
+	return &SandboxActionResponseDto{}, nil
+}
+
+func (d *dryRunClient) GetSandboxStatus(ctx context.Context, sandboxId string) (*SandboxStatusDto, error) {
+	if d.deleted(sandboxId) {
+		return nil, notFound("sandbox", sandboxId)
+	}
+	sandbox, ok := d.synthetic(sandboxId)
+	if !ok {
+		return d.Client.GetSandboxStatus(ctx, sandboxId)
+	}
+	return &SandboxStatusDto{Status: sandbox.GetStatus()}, nil
+}
+
+func (d *dryRunClient) CreateSandbox(ctx context.Context, dto CreateSandboxDto) (*CreateSandboxResponseDto, error) {
+	sandbox := d.syntheticSandbox(dto.Name, dto.ProjectId, dto.Shape, float64(dto.MaxLifeSeconds))
+	d.plan.record(d.logger, "CreateSandbox", sandbox.Id, dto, sandbox)
+	return &sandbox, nil
+}
+
+func (d *dryRunClient) CreateSandboxFromImage(ctx context.Context, dto CreateSandboxFromImageDto) (*CreateSandboxFromImageResponseDto, error) {
+	projectId := ""
+	if dto.ProjectId != nil {
+		projectId = *dto.ProjectId
+	}
+	resp := CreateSandboxFromImageResponseDto{
+		Sandbox: d.syntheticSandbox(dto.Name, projectId, "", float64(dto.MaxLifeSeconds)),
+		BookId:  d.plan.syntheticId("book"),
+	}
+	d.plan.record(d.logger, "CreateSandboxFromImage", resp.Sandbox.Id, dto, resp)
+	return &resp, nil
+}
+
+func (d *dryRunClient) DeleteSandbox(ctx context.Context, sandboxId string) error {
+	d.mu.Lock()
+	if _, ok := d.sandboxes[sandboxId]; ok {
+		delete(d.sandboxes, sandboxId)
+	} else {
+		d.deletedSandboxes[sandboxId] = true
+	}
+	delete(d.mappings, sandboxId)
+	d.mu.Unlock()
+	d.plan.record(d.logger, "DeleteSandbox", sandboxId, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) ExtendSandbox(ctx context.Context, sandboxId string, dto ExtendSandboxDto) error {
+	d.plan.record(d.logger, "ExtendSandbox", sandboxId, dto, nil)
+	return nil
+}
+
+func (d *dryRunClient) Restart(ctx context.Context, sandboxId string) error {
+	d.plan.record(d.logger, "Restart", sandboxId, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) ExecuteComputerUseAction(ctx context.Context, sandboxId string, dto ComputerUseActionDto) (*SandboxActionResponseDto, error) {
+	resp := SandboxActionResponseDto{}
+	d.plan.record(d.logger, "ExecuteComputerUseAction", sandboxId, dto, resp)
+	return &resp, nil
+}
+
+func (d *dryRunClient) ExecuteSandboxAction(ctx context.Context, sandboxId string, dto ExecuteSandboxActionDto) (*SandboxActionResponseDto, error) {
+	// fail like the real client would, so that the plan only holds valid actions
+	if dto.Action != nil {
+		if err := dto.Action.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	resp := SandboxActionResponseDto{}
+	d.plan.record(d.logger, "ExecuteSandboxAction", sandboxId, dto, resp)
+	return &resp, nil
+}
+
+func (d *dryRunClient) CopyFilesWithSandbox(ctx context.Context, sandboxId string, dto SandboxFileCopyRequestDto) (*SandboxFileCopyResponseDto, error) {
+	resp := SandboxFileCopyResponseDto{Results: make([]SandboxFileCopyResponseDtoResults, len(dto.Files))}
+	for i, file := range dto.Files {
+		resp.Results[i] = SandboxFileCopyResponseDtoResults{Id: file.Id, Success: true}
+	}
+	d.plan.record(d.logger, "CopyFilesWithSandbox", sandboxId, dto, resp)
+	return &resp, nil
+}
+
+func (d *dryRunClient) ExecSandboxProcess(ctx context.Context, sandboxId string, dto SandboxProcessRequestDto) (*SandboxProcessResponseDto, error) {
+	resp := SandboxProcessResponseDto{}
+	d.plan.record(d.logger, "ExecSandboxProcess", sandboxId, dto, resp)
+	return &resp, nil
+}
+
+func (d *dryRunClient) CreateProject(ctx context.Context, dto CreateProjectDto) (*SingleProjectResponseDto, error) {
+	project := SingleProjectResponseDto{
+		Id:        d.plan.syntheticId("project"),
+		Name:      dto.Name,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	d.plan.record(d.logger, "CreateProject", project.Id, dto, project)
+	return &project, nil
+}
+
+func (d *dryRunClient) DeleteProject(ctx context.Context, projectId string) error {
+	d.plan.record(d.logger, "DeleteProject", projectId, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) CreateMachineImage(ctx context.Context, dto CreateMachineImageDto) (*MachineImageResponseDto, error) {
+	image := MachineImageResponseDto{
+		Id:          d.plan.syntheticId("image"),
+		Name:        dto.Name,
+		Description: dto.Description,
+		CreatedAt:   time.Now(),
+		Scope:       "ORG",
+		Status:      string(MachineImageReady),
+	}
+	if sandbox, ok := d.synthetic(dto.SandboxId); ok {
+		image.ShapeName = sandbox.ShapeName
+	}
+	d.mu.Lock()
+	d.images[image.Id] = image
+	d.mu.Unlock()
+	d.plan.record(d.logger, "CreateMachineImage", image.Id, dto, image)
+	return &image, nil
+}
+
+// ListMachineImages adds the images created in dry-run to the org scope and the quota,
+// and removes the images deleted in dry-run.
+func (d *dryRunClient) ListMachineImages(ctx context.Context, scope string) (*MachineImagesResponseDto, error) {
+	images, err := d.Client.ListMachineImages(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	images.Images = slices.DeleteFunc(images.Images, func(image MachineImagesResponseDtoImages) bool {
+		if d.deletedImages[image.Id] {
+			if strings.EqualFold(image.Scope, "org") {
+				images.Quota.Used--
+			}
+			return true
+		}
+		return false
+	})
+	if scope != "" && !strings.EqualFold(scope, "org") && !strings.EqualFold(scope, "all") {
+		return images, nil
+	}
+	for _, image := range d.images {
+		images.Images = append(images.Images, MachineImagesResponseDtoImages{
+			Id:          image.Id,
+			Name:        image.Name,
+			Description: image.Description,
+			CreatedAt:   image.CreatedAt,
+			ShapeName:   image.ShapeName,
+			Scope:       image.Scope,
+			Status:      image.Status,
+		})
+		images.Quota.Used++
+	}
+	return images, nil
+}
+
+func (d *dryRunClient) DeleteMachineImage(ctx context.Context, imageId string) error {
+	d.mu.Lock()
+	if _, ok := d.images[imageId]; ok {
+		delete(d.images, imageId)
+	} else {
+		d.deletedImages[imageId] = true
+	}
+	d.mu.Unlock()
+	d.plan.record(d.logger, "DeleteMachineImage", imageId, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) CreateHttpPortMapping(ctx context.Context, sandboxId string, targetEndpoint string) (*CreateHttpMappingResponseDto, error) {
+	mapping := CreateHttpMappingResponseDto{
+		Domain:         d.plan.syntheticId("mapping") + ".invalid",
+		TargetEndpoint: targetEndpoint,
+	}
+	d.mu.Lock()
+	d.mappings[sandboxId] = append(d.mappings[sandboxId], mapping)
+	d.mu.Unlock()
+	d.plan.record(d.logger, "CreateHttpPortMapping", sandboxId, map[string]string{"targetEndpoint": targetEndpoint}, mapping)
+	return &mapping, nil
+}
+
+func (d *dryRunClient) GetHttpPortMapping(ctx context.Context, sandboxId string, targetEndpoint string) (*GetHttpMappingResponseDto, error) {
+	d.mu.Lock()
+	for _, mapping := range d.mappings[sandboxId] {
+		if mapping.TargetEndpoint == targetEndpoint {
+			d.mu.Unlock()
+			return &mapping, nil
+		}
+	}
+	removed := d.deletedMappings[mappingKey{sandboxId, targetEndpoint}]
+	d.mu.Unlock()
+	if d.deleted(sandboxId) {
+		return nil, notFound("sandbox", sandboxId)
+	}
+	if _, ok := d.synthetic(sandboxId); ok || removed {
+		return nil, notFound("http port mapping", targetEndpoint)
+	}
+	return d.Client.GetHttpPortMapping(ctx, sandboxId, targetEndpoint)
+}
+
+func (d *dryRunClient) ListHttpPortMappings(ctx context.Context, sandboxId string) ([]HttpMappingResponseDto, error) {
+	if d.deleted(sandboxId) {
+		return nil, notFound("sandbox", sandboxId)
+	}
+	var mappings []HttpMappingResponseDto
+	if _, ok := d.synthetic(sandboxId); !ok {
+		var err error
+		if mappings, err = d.Client.ListHttpPortMappings(ctx, sandboxId); err != nil {
+			return nil, err
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	mappings = slices.DeleteFunc(mappings, func(mapping HttpMappingResponseDto) bool {
+		return d.deletedMappings[mappingKey{sandboxId, mapping.TargetEndpoint}]
+	})
+	return append(mappings, d.mappings[sandboxId]...), nil
+}
+
+func (d *dryRunClient) DeleteHttpPortMapping(ctx context.Context, sandboxId string, targetEndpoint string) error {
+	d.mu.Lock()
+	created := len(d.mappings[sandboxId])
+	d.mappings[sandboxId] = slices.DeleteFunc(d.mappings[sandboxId], func(mapping HttpMappingResponseDto) bool {
+		return mapping.TargetEndpoint == targetEndpoint
+	})
+	if len(d.mappings[sandboxId]) == created {
+		d.deletedMappings[mappingKey{sandboxId, targetEndpoint}] = true
+	}
+	d.mu.Unlock()
+	d.plan.record(d.logger, "DeleteHttpPortMapping", sandboxId, map[string]string{"targetEndpoint": targetEndpoint}, nil)
+	return nil
+}
+
+func (d *dryRunClient) CreateSandboxShellCommand(ctx context.Context, sandboxId string, dto SandboxShellCommandCreateRequestDto) (*SandboxShellCommandCreateResponseDto, error) {
+	resp := SandboxShellCommandCreateResponseDto{SessionId: d.plan.syntheticId("shell")}
+	d.plan.record(d.logger, "CreateSandboxShellCommand", sandboxId, dto, resp)
+	return &resp, nil
+}
+
+func (d *dryRunClient) CreateSandboxShellCommandStream(ctx context.Context, sandboxId string, dto SandboxShellCommandStreamCreateRequestDto) (<-chan SandboxShellStreamEvent, error) {
+	d.plan.record(d.logger, "CreateSandboxShellCommandStream", sandboxId, dto, nil)
+	events := make(chan SandboxShellStreamEvent, 1)
+	events <- SandboxShellStreamEvent{Type: SandboxShellStreamEventEnd}
+	close(events)
+	return events, nil
+}
+
+func (d *dryRunClient) WriteSandboxShellCommand(ctx context.Context, sandboxId string, shellId string, dto SandboxShellCommandWriteRequestDto) error {
+	d.plan.record(d.logger, "WriteSandboxShellCommand", sandboxId+"/"+shellId, dto, nil)
+	return nil
+}
+
+func (d *dryRunClient) FinishSandboxShellCommand(ctx context.Context, sandboxId string, shellId string) error {
+	d.plan.record(d.logger, "FinishSandboxShellCommand", sandboxId+"/"+shellId, nil, nil)
+	return nil
+}
+
+func (d *dryRunClient) ReadSandboxShellCommand(ctx context.Context, sandboxId string, shellId string) (*SandboxShellCommandReadResponseDto, error) {
+	return &SandboxShellCommandReadResponseDto{Outputs: []SandboxShellCommandOutput{}}, nil
+}
+
+func (d *dryRunClient) TerminateSandboxShellCommand(ctx context.Context, sandboxId string, shellId string) error {
+	d.plan.record(d.logger, "TerminateSandboxShellCommand", sandboxId+"/"+shellId, nil, nil)
+	return nil
+}
+
+// dryRunMcp is an Mcp that records mutating calls in a plan instead of sending them.
+type dryRunMcp struct {
+	Mcp
+	plan   *DryRunPlan
+	logger Logger
+
+	mu      sync.Mutex
+	servers map[string]McpServerResponseDto
+	deleted map[string]bool
+}
+
+// NewDryRunMcp wraps an MCP client like NewDryRunClient: servers are listed through the
+// API while creating, deleting, attaching servers and calling tools are only planned.
+// ListMcpServers includes the servers created in dry-run and hides the deleted ones.
+// If plan is nil, a new plan is used and the operations are only logged.
+func NewDryRunMcp(m Mcp, plan *DryRunPlan, logger Logger) Mcp {
+	if plan == nil {
+		plan = NewDryRunPlan()
+	}
+	if logger == nil {
+		logger = emptyLogger{}
+	}
+	return &dryRunMcp{
+		Mcp:     m,
+		plan:    plan,
+		logger:  logger,
+		servers: make(map[string]McpServerResponseDto),
+		deleted: make(map[string]bool),
+	}
+}
+
+func (d *dryRunMcp) ListMcpServers(ctx context.Context) ([]McpServerResponseDto, error) {
+	servers, err := d.Mcp.ListMcpServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	servers = slices.DeleteFunc(servers, func(server McpServerResponseDto) bool {
+		return d.deleted[server.Id]
+	})
+	for _, server := range d.servers {
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+func (d *dryRunMcp) CreateMcpServer(ctx context.Context, dto CreateMcpServerDto) (*McpServerResponseDto, error) {
+	server := McpServerResponseDto{
+		Id:        d.plan.syntheticId("mcp"),
+		Name:      dto.Name,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		ProjectId: dto.ProjectId,
+		Policy: McpServerResponseDtoPolicy{
+			SandboxShape:              dto.SandboxShape,
+			SandboxMaxLifetimeSeconds: dto.SandboxMaxLifetimeSeconds,
+			SandboxMaxIdleTimeSeconds: dto.SandboxMaxIdleTimeSeconds,
+			SandboxAutoCreation:       dto.SandboxAutoCreation,
+			SandboxExposeRecreateTool: dto.SandboxExposeRecreateTool,
+			SandboxExposeRestartTool:  dto.SandboxExposeRestartTool,
+			SandboxExposeDeleteTool:   dto.SandboxExposeDeleteTool,
+		},
+	}
+	d.mu.Lock()
+	d.servers[server.Id] = server
+	d.mu.Unlock()
+	d.plan.record(d.logger, "CreateMcpServer", server.Id, dto, server)
+	return &server, nil
+}
+
+func (d *dryRunMcp) DeleteMcpServer(ctx context.Context, mcpServerId string) error {
+	d.mu.Lock()
+	if _, ok := d.servers[mcpServerId]; ok {
+		delete(d.servers, mcpServerId)
+	} else {
+		d.deleted[mcpServerId] = true
+	}
+	d.mu.Unlock()
+	d.plan.record(d.logger, "DeleteMcpServer", mcpServerId, nil, nil)
+	return nil
+}
+
+func (d *dryRunMcp) SetMcpServerToSandbox(ctx context.Context, mcpServerId string, dto SetMcpServerToSandboxResponseDto) error {
+	d.plan.record(d.logger, "SetMcpServerToSandbox", mcpServerId, dto, nil)
+	return nil
+}
+
+func (d *dryRunMcp) CallTools(ctx context.Context, args map[string]any, service *string) (*mcp.CallToolResult, error) {
+	target := "computer-use"
+	if service != nil {
+		target = *service
+	}
+	result := &mcp.CallToolResult{Content: []mcp.Content{}}
+	d.plan.record(d.logger, "CallTools", target, args, result)
+	return result, nil
+}
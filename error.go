@@ -3,6 +3,8 @@ package lybic
 type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// StatusCode is the HTTP status of the response that carried the error
+	StatusCode int `json:"-"`
 }
 
 func (e Error) Error() string {
@@ -7,6 +7,7 @@ require (
 	github.com/modelcontextprotocol/go-sdk v1.3.1
 	github.com/openai/openai-go v1.12.0
 	github.com/sashabaranov/go-openai v1.41.2
+	golang.org/x/image v0.25.0
 	google.golang.org/grpc v1.64.1
 	google.golang.org/protobuf v1.36.11
 )
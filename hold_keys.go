@@ -0,0 +1,64 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// HoldKeys presses the keys down in order, runs fn and releases the keys in reverse order.
+//
+//	The keys are always released, also when fn fails, panics or ctx is canceled, so that
+//	a modifier is never left pressed in the sandbox. Keys that failed to be pressed are not
+//	released. The returned error joins the error of fn with the release errors.
+//
+//	err := lybic.HoldKeys(ctx, client, sandboxId, []string{"shift"}, func(ctx context.Context) error {
+//		for _, action := range clicks { ... }
+//	})
+func HoldKeys(ctx context.Context, c Client, sandboxId string, keys []string, fn func(ctx context.Context) error) (err error) {
+	pressed := make([]string, 0, len(keys))
+	defer func() {
+		// release even if ctx is done, the sandbox must not keep keys pressed
+		releaseCtx := context.WithoutCancel(ctx)
+		for i := len(pressed) - 1; i >= 0; i-- {
+			_, releaseErr := c.ExecuteSandboxAction(releaseCtx, sandboxId, ExecuteSandboxActionDto{
+				Action: NewKeyUpAction(pressed[i]),
+			})
+			if releaseErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to release key %s: %w", pressed[i], releaseErr))
+			}
+		}
+	}()
+
+	for _, key := range keys {
+		if _, err := c.ExecuteSandboxAction(ctx, sandboxId, ExecuteSandboxActionDto{
+			Action: NewKeyDownAction(key),
+		}); err != nil {
+			return fmt.Errorf("failed to press key %s: %w", key, err)
+		}
+		pressed = append(pressed, key)
+	}
+	return fn(ctx)
+}
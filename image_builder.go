@@ -0,0 +1,501 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+const (
+	recipeHashMarker         = "recipe-hash:"
+	builderSandboxPollPeriod = 3 * time.Second
+	// builderRestartTimeout bounds the wait for a restarting sandbox to leave RUNNING
+	builderRestartTimeout = time.Minute
+	// shellExitMarker prefixes the line that carries the exit status of a shell step
+	shellExitMarker = "__lybic_image_builder_exit="
+)
+
+var (
+	ErrRecipeNoBase      = errors.New("image recipe requires a base shape or a base image")
+	ErrRecipeNoName      = errors.New("image recipe requires an output name")
+	ErrRecipeInvalidStep = errors.New("image recipe step must set exactly one of shell, exec, copy or reboot")
+	// ErrShellStepIncomplete is returned when the output stream of a shell step ends
+	// before the command reported its exit status.
+	ErrShellStepIncomplete = errors.New("shell step ended without an exit status")
+)
+
+// ImageRecipe describes how to bake a machine image.
+//
+//	The builder provisions a sandbox from BaseShape or BaseImageId, runs Steps in order
+//	and snapshots the sandbox as Name. Only the base and the steps are part of the recipe
+//	hash, so renaming the output does not trigger a rebuild. Recipes can be loaded from JSON.
+type ImageRecipe struct {
+	// Name is the name of the output image
+	Name string `json:"name"`
+	// Description is the description of the output image, the recipe hash is appended to it
+	Description string `json:"description,omitempty"`
+
+	// BaseShape is the shape of a fresh sandbox to start from
+	BaseShape string `json:"baseShape,omitempty"`
+	// BaseImageId is the machine image to start from, takes precedence over BaseShape
+	BaseImageId string `json:"baseImageId,omitempty"`
+	// ProjectId is the project of the build sandbox, empty uses the default project
+	ProjectId string `json:"projectId,omitempty"`
+
+	Steps []RecipeStep `json:"steps"`
+}
+
+// RecipeStep is one step of an ImageRecipe, exactly one field must be set.
+type RecipeStep struct {
+	Shell  *SandboxShellCommandStreamCreateRequestDto `json:"shell,omitempty"`
+	Exec   *SandboxProcessRequestDto                  `json:"exec,omitempty"`
+	Copy   *SandboxFileCopyRequestDto                 `json:"copy,omitempty"`
+	Reboot bool                                       `json:"reboot,omitempty"`
+}
+
+// ShellStep runs a shell command in the build sandbox, a non-zero exit status fails the build.
+//
+//	On Linux and Android the command runs in a subshell of the streaming shell, followed by
+//	an echo of its exit status. On Windows it runs with PowerShell through ExecSandboxProcess,
+//	so its output is only written once it finishes.
+func ShellStep(command string) RecipeStep {
+	return RecipeStep{Shell: &SandboxShellCommandStreamCreateRequestDto{Command: command}}
+}
+
+// ExecStep runs a process in the build sandbox, a non-zero exit code fails the build.
+func ExecStep(executable string, args ...string) RecipeStep {
+	return RecipeStep{Exec: &SandboxProcessRequestDto{Executable: executable, Args: args}}
+}
+
+// CopyStep copies files to or from the build sandbox.
+func CopyStep(files ...SandboxFileCopyRequestDtoFiles) RecipeStep {
+	return RecipeStep{Copy: &SandboxFileCopyRequestDto{Files: files}}
+}
+
+// RebootStep restarts the build sandbox and waits until it is running again.
+func RebootStep() RecipeStep {
+	return RecipeStep{Reboot: true}
+}
+
+func (s RecipeStep) String() string {
+	switch {
+	case s.Shell != nil:
+		return fmt.Sprintf("shell %q", s.Shell.Command)
+	case s.Exec != nil:
+		return fmt.Sprintf("exec %s %s", s.Exec.Executable, strings.Join(s.Exec.Args, " "))
+	case s.Copy != nil:
+		return fmt.Sprintf("copy %d files", len(s.Copy.Files))
+	default:
+		return "reboot"
+	}
+}
+
+func (s RecipeStep) validate() error {
+	n := 0
+	if s.Shell != nil {
+		n++
+	}
+	if s.Exec != nil {
+		n++
+	}
+	if s.Copy != nil {
+		n++
+	}
+	if s.Reboot {
+		n++
+	}
+	if n != 1 {
+		return ErrRecipeInvalidStep
+	}
+	return nil
+}
+
+// Validate checks that the recipe can be built.
+func (r ImageRecipe) Validate() error {
+	if r.Name == "" {
+		return ErrRecipeNoName
+	}
+	if r.BaseShape == "" && r.BaseImageId == "" {
+		return ErrRecipeNoBase
+	}
+	for i, step := range r.Steps {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("step %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Hash returns the hex encoded SHA-256 of the recipe base and steps.
+func (r ImageRecipe) Hash() (string, error) {
+	data, err := json.Marshal(struct {
+		BaseShape   string       `json:"baseShape,omitempty"`
+		BaseImageId string       `json:"baseImageId,omitempty"`
+		Steps       []RecipeStep `json:"steps"`
+	}{r.BaseShape, r.BaseImageId, r.Steps})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ImageBuildResult is the outcome of ImageBuilder.Build.
+type ImageBuildResult struct {
+	Image *MachineImageResponseDto
+	// Hash is the recipe hash recorded in the image description
+	Hash string
+	// Skipped is true when an image built from the same recipe already existed
+	Skipped bool
+	// SandboxId is the build sandbox, empty when skipped
+	SandboxId string
+}
+
+// ImageBuilder bakes machine images from recipes.
+type ImageBuilder struct {
+	client Client
+	logger Logger
+
+	// Output receives the output of shell and exec steps, can be nil
+	Output io.Writer
+
+	// KeepSandboxOnFailure leaves the build sandbox running when a step fails, for debugging
+	KeepSandboxOnFailure bool
+
+	// EvictionPolicy is passed to PreflightMachineImageQuota before provisioning, nil never evicts
+	EvictionPolicy *ImageEvictionPolicy
+
+	// MaxLifeSeconds is the life time of the build sandbox, defaults to 3600
+	MaxLifeSeconds int32
+}
+
+// NewImageBuilder creates an image builder that uses the client.
+func NewImageBuilder(client Client) *ImageBuilder {
+	return &ImageBuilder{
+		client:         client,
+		logger:         loggerOf(client),
+		MaxLifeSeconds: 3600,
+	}
+}
+
+// FindImageByRecipe returns the READY org image built from a recipe with the given hash, or nil.
+func FindImageByRecipe(ctx context.Context, c Client, hash string) (*MachineImageResponseDto, error) {
+	images, err := c.ListMachineImages(ctx, "org")
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images.Images {
+		if image.GetStatus() == MachineImageReady && image.Description != nil &&
+			strings.Contains(*image.Description, recipeHashMarker+hash) {
+			return &image, nil
+		}
+	}
+	return nil, nil
+}
+
+// Build bakes the recipe into a machine image and waits until it is READY.
+//
+//	If an image built from the same recipe hash already exists, it is returned with
+//	Skipped set and nothing is provisioned. The build sandbox is deleted when the build
+//	ends, unless KeepSandboxOnFailure is set and the build failed.
+func (b *ImageBuilder) Build(ctx context.Context, recipe ImageRecipe) (result *ImageBuildResult, err error) {
+	if err := recipe.Validate(); err != nil {
+		return nil, err
+	}
+	hash, err := recipe.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := FindImageByRecipe(ctx, b.client, hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		b.logger.Infof("image builder: image %s already built from recipe %s, skipping", existing.Id, hash[:12])
+		return &ImageBuildResult{Image: existing, Hash: hash, Skipped: true}, nil
+	}
+
+	// the quota is checked before provisioning, so a full quota does not cost a build
+	if _, err := PreflightMachineImageQuota(ctx, b.client, b.EvictionPolicy); err != nil {
+		return nil, err
+	}
+	sandboxId, err := b.provision(ctx, recipe)
+	if err != nil {
+		return nil, err
+	}
+	result = &ImageBuildResult{Hash: hash, SandboxId: sandboxId}
+	defer func() {
+		if err != nil && b.KeepSandboxOnFailure {
+			b.logger.Warnf("image builder: build failed, keeping sandbox %s", sandboxId)
+			return
+		}
+		if deleteErr := b.client.DeleteSandbox(context.WithoutCancel(ctx), sandboxId); deleteErr != nil {
+			b.logger.Errorf("image builder: failed to delete sandbox %s: %v", sandboxId, deleteErr)
+		}
+	}()
+
+	if err := b.waitRunning(ctx, sandboxId); err != nil {
+		return result, err
+	}
+	detail, err := b.client.GetSandbox(ctx, sandboxId)
+	if err != nil {
+		return result, err
+	}
+	osName := detail.Sandbox.Shape.Os
+	for i, step := range recipe.Steps {
+		b.logger.Infof("image builder: step %d/%d: %s", i+1, len(recipe.Steps), step)
+		if err := b.runStep(ctx, sandboxId, osName, step); err != nil {
+			return result, fmt.Errorf("step %d (%s): %w", i+1, step, err)
+		}
+	}
+
+	description := strings.TrimSpace(recipe.Description + " " + recipeHashMarker + hash)
+	image, err := b.client.CreateMachineImage(ctx, CreateMachineImageDto{
+		SandboxId:   sandboxId,
+		Name:        recipe.Name,
+		Description: &description,
+	})
+	if err != nil {
+		return result, err
+	}
+	b.logger.Infof("image builder: waiting for image %s", image.Id)
+	result.Image, err = WaitForMachineImage(ctx, b.client, image.Id)
+	return result, err
+}
+
+func (b *ImageBuilder) provision(ctx context.Context, recipe ImageRecipe) (string, error) {
+	name := "image-builder-" + recipe.Name
+	if recipe.BaseImageId != "" {
+		var projectId *string
+		if recipe.ProjectId != "" {
+			projectId = &recipe.ProjectId
+		}
+		resp, err := b.client.CreateSandboxFromImage(ctx, CreateSandboxFromImageDto{
+			ImageId:        recipe.BaseImageId,
+			Name:           name,
+			MaxLifeSeconds: b.MaxLifeSeconds,
+			ProjectId:      projectId,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Sandbox.Id, nil
+	}
+
+	sandbox, err := b.client.CreateSandbox(ctx, CreateSandboxDto{
+		Name:           name,
+		MaxLifeSeconds: float32(b.MaxLifeSeconds),
+		ProjectId:      recipe.ProjectId,
+		Shape:          recipe.BaseShape,
+	})
+	if err != nil {
+		return "", err
+	}
+	return sandbox.Id, nil
+}
+
+func (b *ImageBuilder) waitRunning(ctx context.Context, sandboxId string) error {
+	ticker := time.NewTicker(builderSandboxPollPeriod)
+	defer ticker.Stop()
+
+	for {
+		status, err := b.client.GetSandboxStatus(ctx, sandboxId)
+		if err != nil {
+			return err
+		}
+		switch status.Status {
+		case SandboxRunning:
+			return nil
+		case SandboxError, SandboxStopped:
+			return fmt.Errorf("sandbox %s is %s", sandboxId, status.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitRestarted waits for a restarted sandbox to leave RUNNING, so that waitRunning
+// does not return on the status from before the restart. A restart too quick to be
+// observed is assumed to have happened after builderRestartTimeout.
+func (b *ImageBuilder) waitRestarted(ctx context.Context, sandboxId string) error {
+	ticker := time.NewTicker(builderSandboxPollPeriod)
+	defer ticker.Stop()
+	deadline := time.Now().Add(builderRestartTimeout)
+
+	for time.Now().Before(deadline) {
+		status, err := b.client.GetSandboxStatus(ctx, sandboxId)
+		if err != nil {
+			return err
+		}
+		if status.Status != SandboxRunning {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	b.logger.Warnf("image builder: sandbox %s did not leave RUNNING after restart", sandboxId)
+	return nil
+}
+
+func (b *ImageBuilder) runStep(ctx context.Context, sandboxId, osName string, step RecipeStep) error {
+	switch {
+	case step.Shell != nil && osName == "Windows":
+		if step.Shell.TimeoutSeconds != nil {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(*step.Shell.TimeoutSeconds)*time.Second)
+			defer cancel()
+		}
+		dto := *powershell(step.Shell.Command)
+		if step.Shell.WorkingDirectory != nil {
+			dto.WorkingDirectory = *step.Shell.WorkingDirectory
+		}
+		return b.exec(ctx, sandboxId, dto)
+
+	case step.Shell != nil:
+		return b.runShell(ctx, sandboxId, *step.Shell)
+
+	case step.Exec != nil:
+		return b.exec(ctx, sandboxId, *step.Exec)
+
+	case step.Copy != nil:
+		resp, err := b.client.CopyFilesWithSandbox(ctx, sandboxId, *step.Copy)
+		if err != nil {
+			return err
+		}
+		for _, r := range resp.Results {
+			if !r.Success {
+				return fmt.Errorf("copy %s failed: %s", r.Id, r.Error)
+			}
+		}
+		return nil
+
+	default:
+		if err := b.client.Restart(ctx, sandboxId); err != nil {
+			return err
+		}
+		if err := b.waitRestarted(ctx, sandboxId); err != nil {
+			return err
+		}
+		return b.waitRunning(ctx, sandboxId)
+	}
+}
+
+func (b *ImageBuilder) exec(ctx context.Context, sandboxId string, dto SandboxProcessRequestDto) error {
+	resp, err := b.client.ExecSandboxProcess(ctx, sandboxId, dto)
+	if err != nil {
+		return err
+	}
+	stdout, _ := base64.StdEncoding.DecodeString(resp.StdoutBase64)
+	stderr, _ := base64.StdEncoding.DecodeString(resp.StderrBase64)
+	b.output(string(stdout))
+	b.output(string(stderr))
+	if resp.ExitCode != 0 {
+		return fmt.Errorf("process exited with code %d: %s", resp.ExitCode, strings.TrimSpace(string(stderr)))
+	}
+	return nil
+}
+
+// runShell runs the command in a subshell followed by an echo of its exit status, and
+// requires the stream to end with the waiting event. The status line is not written
+// to Output.
+func (b *ImageBuilder) runShell(ctx context.Context, sandboxId string, dto SandboxShellCommandStreamCreateRequestDto) error {
+	dto.Command = "(\n" + dto.Command + "\n)\necho \"" + shellExitMarker + "$?\""
+	// returning before the stream ends must stop the goroutine reading it
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := b.client.CreateSandboxShellCommandStream(ctx, sandboxId, dto)
+	if err != nil {
+		return err
+	}
+
+	exitCode := -1
+	stdoutLine := func(line string) {
+		if code, ok := strings.CutPrefix(strings.TrimRight(line, "\r\n"), shellExitMarker); ok {
+			if n, err := strconv.Atoi(code); err == nil {
+				exitCode = n
+				return
+			}
+		}
+		b.output(line)
+	}
+
+	var pending string
+	completed := false
+	for event := range events {
+		switch event.Type {
+		case SandboxShellStreamEventStdout:
+			lines := strings.SplitAfter(pending+event.Data, "\n")
+			pending = lines[len(lines)-1]
+			for _, line := range lines[:len(lines)-1] {
+				stdoutLine(line)
+			}
+		case SandboxShellStreamEventStderr:
+			b.output(event.Data)
+		case SandboxShellStreamEventTimeout:
+			return fmt.Errorf("shell command timed out: %s", event.Data)
+		case SandboxShellStreamEventWaiting:
+			completed = true
+		}
+	}
+	stdoutLine(pending)
+
+	if !completed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return ErrShellStepIncomplete
+	}
+	if exitCode < 0 {
+		return ErrShellStepIncomplete
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("shell command exited with code %d", exitCode)
+	}
+	return nil
+}
+
+func (b *ImageBuilder) output(data string) {
+	if b.Output != nil && data != "" {
+		_, _ = io.WriteString(b.Output, data)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	ErrRetentionPolicyEmpty      = errors.New("retention policy must set KeepLatest, MaxAge or DeleteErrored")
+	ErrRetentionKeepLatestGroups = errors.New("retention policy KeepLatest requires Prefixes to group images")
+)
+
+// ImageRetentionPolicy describes which org machine images are garbage collected.
+//
+//	An image is deleted if any rule selects it, unless it is pinned, still being
+//	created or kept by KeepLatest. Public images are never touched.
+type ImageRetentionPolicy struct {
+	// Prefixes groups images by name prefix for KeepLatest, an image belongs to the
+	// longest prefix it matches. Images matching no prefix are only subject to MaxAge
+	// and DeleteErrored.
+	Prefixes []string
+
+	// KeepLatest keeps the N newest READY images of every prefix group and deletes the rest,
+	// zero disables the rule. Kept images are exempt from MaxAge.
+	KeepLatest int
+
+	// MaxAge deletes images created more than MaxAge ago, zero disables the rule
+	MaxAge time.Duration
+
+	// DeleteErrored deletes images in the ERROR status
+	DeleteErrored bool
+
+	// Pinned holds image IDs that are never deleted
+	Pinned []string
+
+	// DryRun reports the decisions without deleting anything
+	DryRun bool
+}
+
+// ImageRetentionDecision is the verdict of the retention policy for one image.
+type ImageRetentionDecision struct {
+	Image MachineImagesResponseDtoImages
+	// Delete is true when the image is deleted, or would be in dry-run mode
+	Delete bool
+	// Reason explains the decision
+	Reason string
+}
+
+// ImageRetentionReport is the outcome of GarbageCollectMachineImages.
+type ImageRetentionReport struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	DryRun     bool
+	Quota      MachineImagesResponseDtoQuota
+	// Decisions holds one entry per org image, oldest first
+	Decisions []ImageRetentionDecision
+	// Deleted are the IDs of images deleted in this pass
+	Deleted []string
+	// Failed maps image IDs to the error returned by DeleteMachineImage
+	Failed map[string]error
+}
+
+// WriteTo prints the decisions as a table, which is the dry-run output.
+func (r *ImageRetentionReport) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	tw := tabwriter.NewWriter(cw, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tID\tNAME\tSTATUS\tCREATED\tREASON")
+	for _, d := range r.Decisions {
+		action := "keep"
+		if d.Delete {
+			action = "delete"
+			if r.DryRun {
+				action = "would delete"
+			}
+			if _, failed := r.Failed[d.Image.Id]; failed {
+				action = "failed"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", action, d.Image.Id, d.Image.Name, d.Image.Status,
+			d.Image.CreatedAt.Format(time.RFC3339), d.Reason)
+	}
+	if err := tw.Flush(); err != nil {
+		return cw.n, err
+	}
+	_, err := fmt.Fprintf(cw, "quota: %d of %d used, %d to delete\n", r.Quota.Used, r.Quota.Limit, r.countDeletes())
+	return cw.n, err
+}
+
+func (r *ImageRetentionReport) countDeletes() int {
+	n := 0
+	for _, d := range r.Decisions {
+		if d.Delete {
+			n++
+		}
+	}
+	return n
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// GarbageCollectMachineImages applies the retention policy to the org machine images.
+//
+//	Deletion failures are recorded in ImageRetentionReport.Failed, the returned error
+//	is only set when the policy is invalid or the images could not be listed.
+func GarbageCollectMachineImages(ctx context.Context, c Client, policy ImageRetentionPolicy) (*ImageRetentionReport, error) {
+	if policy.KeepLatest <= 0 && policy.MaxAge <= 0 && !policy.DeleteErrored {
+		return nil, ErrRetentionPolicyEmpty
+	}
+	if policy.KeepLatest > 0 && len(policy.Prefixes) == 0 {
+		return nil, ErrRetentionKeepLatestGroups
+	}
+
+	images, err := c.ListMachineImages(ctx, "org")
+	if err != nil {
+		return nil, err
+	}
+	report := &ImageRetentionReport{
+		StartedAt: time.Now(),
+		DryRun:    policy.DryRun,
+		Quota:     images.Quota,
+		Decisions: policy.decide(images.Images, time.Now()),
+		Failed:    make(map[string]error),
+	}
+
+	logger := loggerOf(c)
+	for _, d := range report.Decisions {
+		if !d.Delete {
+			continue
+		}
+		if policy.DryRun {
+			logger.Infof("image gc: would delete %s (%s): %s", d.Image.Id, d.Image.Name, d.Reason)
+			continue
+		}
+		if err := c.DeleteMachineImage(ctx, d.Image.Id); err != nil {
+			logger.Errorf("image gc: failed to delete %s: %v", d.Image.Id, err)
+			report.Failed[d.Image.Id] = err
+			continue
+		}
+		report.Deleted = append(report.Deleted, d.Image.Id)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (p ImageRetentionPolicy) decide(images []MachineImagesResponseDtoImages, now time.Time) []ImageRetentionDecision {
+	var org []MachineImagesResponseDtoImages
+	for _, image := range images {
+		if strings.EqualFold(image.Scope, "org") {
+			org = append(org, image)
+		}
+	}
+	// newest first, so that the first KeepLatest READY images of a group are kept
+	slices.SortFunc(org, func(a, b MachineImagesResponseDtoImages) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	kept := make(map[string]int)
+	decisions := make([]ImageRetentionDecision, len(org))
+	for i, image := range org {
+		d := ImageRetentionDecision{Image: image, Reason: "no rule matched"}
+		group, grouped := p.group(image.Name)
+		keepLatest := p.KeepLatest > 0 && grouped && image.GetStatus() == MachineImageReady
+
+		switch {
+		case slices.Contains(p.Pinned, image.Id):
+			d.Reason = "pinned"
+		case image.GetStatus() == MachineImageCreating:
+			d.Reason = "still creating"
+		case p.DeleteErrored && image.GetStatus() == MachineImageError:
+			d.Delete, d.Reason = true, "status ERROR"
+		case keepLatest && kept[group] < p.KeepLatest:
+			kept[group]++
+			d.Reason = fmt.Sprintf("latest %d of %q", kept[group], group)
+		case p.MaxAge > 0 && now.Sub(image.CreatedAt) > p.MaxAge:
+			d.Delete, d.Reason = true, fmt.Sprintf("older than %s", p.MaxAge)
+		case keepLatest:
+			d.Delete, d.Reason = true, fmt.Sprintf("beyond latest %d of %q", p.KeepLatest, group)
+		}
+		decisions[len(org)-1-i] = d
+	}
+	return decisions
+}
+
+// group returns the longest prefix matching the name.
+func (p ImageRetentionPolicy) group(name string) (string, bool) {
+	best, found := "", false
+	for _, prefix := range p.Prefixes {
+		if strings.HasPrefix(name, prefix) && (!found || len(prefix) > len(best)) {
+			best, found = prefix, true
+		}
+	}
+	return best, found
+}
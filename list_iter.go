@@ -0,0 +1,254 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"iter"
+	"path"
+	"slices"
+	"time"
+)
+
+// ListFilter selects items returned by the All* iterators on the client side.
+//
+//	Build it with NewListFilter and chain the selectors. Selectors that do not apply
+//	to a resource are ignored, e.g. Status has no effect on projects. A nil filter
+//	matches everything.
+//
+//	filter := lybic.NewListFilter().Status(lybic.SandboxRunning).NameGlob("ci-*")
+//	for sandbox, err := range lybic.AllSandboxes(ctx, client, filter) { ... }
+type ListFilter struct {
+	statuses      []SandboxStatus
+	projectId     string
+	shapeName     string
+	nameGlob      string
+	createdBefore time.Time
+	createdAfter  time.Time
+	imageScope    string
+	imageStatuses []MachineImageStatus
+}
+
+// NewListFilter creates an empty filter that matches everything.
+func NewListFilter() *ListFilter {
+	return &ListFilter{}
+}
+
+// Status selects sandboxes in one of the statuses.
+func (f *ListFilter) Status(statuses ...SandboxStatus) *ListFilter {
+	f.statuses = append(f.statuses, statuses...)
+	return f
+}
+
+// Project selects sandboxes and MCP servers of the project.
+func (f *ListFilter) Project(projectId string) *ListFilter {
+	f.projectId = projectId
+	return f
+}
+
+// Shape selects sandboxes and machine images by shape name.
+func (f *ListFilter) Shape(shapeName string) *ListFilter {
+	f.shapeName = shapeName
+	return f
+}
+
+// NameGlob selects items whose name matches the pattern, in path.Match syntax.
+func (f *ListFilter) NameGlob(pattern string) *ListFilter {
+	f.nameGlob = pattern
+	return f
+}
+
+// CreatedBefore selects items created strictly before t.
+func (f *ListFilter) CreatedBefore(t time.Time) *ListFilter {
+	f.createdBefore = t
+	return f
+}
+
+// CreatedAfter selects items created strictly after t.
+func (f *ListFilter) CreatedAfter(t time.Time) *ListFilter {
+	f.createdAfter = t
+	return f
+}
+
+// ImageScope sets the scope passed to ListMachineImages: "org", "public" or "all".
+func (f *ListFilter) ImageScope(scope string) *ListFilter {
+	f.imageScope = scope
+	return f
+}
+
+// ImageStatus selects machine images in one of the statuses.
+func (f *ListFilter) ImageStatus(statuses ...MachineImageStatus) *ListFilter {
+	f.imageStatuses = append(f.imageStatuses, statuses...)
+	return f
+}
+
+func (f *ListFilter) matchName(name string) bool {
+	if f.nameGlob == "" {
+		return true
+	}
+	ok, err := path.Match(f.nameGlob, name)
+	return err == nil && ok
+}
+
+func (f *ListFilter) matchCreated(createdAt time.Time) bool {
+	if !f.createdBefore.IsZero() && !createdAt.Before(f.createdBefore) {
+		return false
+	}
+	if !f.createdAfter.IsZero() && !createdAt.After(f.createdAfter) {
+		return false
+	}
+	return true
+}
+
+// matchCreatedString is matchCreated for DTOs that carry createdAt as a string.
+func (f *ListFilter) matchCreatedString(createdAt string) bool {
+	if f.createdBefore.IsZero() && f.createdAfter.IsZero() {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339, createdAt)
+	return err == nil && f.matchCreated(t)
+}
+
+// MatchSandbox reports whether the sandbox is selected by the filter.
+func (f *ListFilter) MatchSandbox(s CreateSandboxResponseDto) bool {
+	if f == nil {
+		return true
+	}
+	return (len(f.statuses) == 0 || slices.Contains(f.statuses, s.GetStatus())) &&
+		(f.projectId == "" || s.ProjectId == f.projectId) &&
+		(f.shapeName == "" || s.ShapeName == f.shapeName) &&
+		f.matchName(s.Name) &&
+		f.matchCreated(s.CreatedAt)
+}
+
+// MatchProject reports whether the project is selected by the filter.
+func (f *ListFilter) MatchProject(p SingleProjectResponseDto) bool {
+	if f == nil {
+		return true
+	}
+	return (f.projectId == "" || p.Id == f.projectId) &&
+		f.matchName(p.Name) &&
+		f.matchCreatedString(p.CreatedAt)
+}
+
+// MatchMachineImage reports whether the machine image is selected by the filter.
+func (f *ListFilter) MatchMachineImage(i MachineImagesResponseDtoImages) bool {
+	if f == nil {
+		return true
+	}
+	return (len(f.imageStatuses) == 0 || slices.Contains(f.imageStatuses, i.GetStatus())) &&
+		(f.shapeName == "" || i.ShapeName == f.shapeName) &&
+		f.matchName(i.Name) &&
+		f.matchCreated(i.CreatedAt)
+}
+
+// MatchMcpServer reports whether the MCP server is selected by the filter.
+func (f *ListFilter) MatchMcpServer(m McpServerResponseDto) bool {
+	if f == nil {
+		return true
+	}
+	return (f.projectId == "" || m.ProjectId == f.projectId) &&
+		(f.shapeName == "" || m.Policy.SandboxShape == f.shapeName) &&
+		f.matchName(m.Name) &&
+		f.matchCreatedString(m.CreatedAt)
+}
+
+// pageFunc fetches the page identified by token and returns the token of the next
+// page, or an empty token on the last page.
+//
+//	The list APIs are not paginated yet, so every pageFunc currently returns a single
+//	page. Once they are, only the pageFunc needs to change and not the iterators' callers.
+type pageFunc[T any] func(ctx context.Context, token string) (items []T, next string, err error)
+
+// paginate yields every item of every page that satisfies match. On error it yields
+// the zero value with the error once and stops.
+func paginate[T any](ctx context.Context, fetch pageFunc[T], match func(T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		token := ""
+		for {
+			items, next, err := fetch(ctx, token)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if match(item) && !yield(item, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			token = next
+		}
+	}
+}
+
+// AllSandboxes iterates over the sandboxes selected by filter.
+func AllSandboxes(ctx context.Context, c Client, filter *ListFilter) iter.Seq2[CreateSandboxResponseDto, error] {
+	return paginate(ctx, func(ctx context.Context, _ string) ([]CreateSandboxResponseDto, string, error) {
+		sandboxes, err := c.ListSandboxes(ctx)
+		return sandboxes, "", err
+	}, filter.MatchSandbox)
+}
+
+// AllProjects iterates over the projects selected by filter.
+func AllProjects(ctx context.Context, c Client, filter *ListFilter) iter.Seq2[SingleProjectResponseDto, error] {
+	return paginate(ctx, func(ctx context.Context, _ string) ([]SingleProjectResponseDto, string, error) {
+		projects, err := c.ListProjects(ctx)
+		return projects, "", err
+	}, filter.MatchProject)
+}
+
+// AllMachineImages iterates over the machine images selected by filter.
+// The image scope of the filter is passed to ListMachineImages, it defaults to "org".
+func AllMachineImages(ctx context.Context, c Client, filter *ListFilter) iter.Seq2[MachineImagesResponseDtoImages, error] {
+	scope := ""
+	if filter != nil {
+		scope = filter.imageScope
+	}
+	return paginate(ctx, func(ctx context.Context, _ string) ([]MachineImagesResponseDtoImages, string, error) {
+		images, err := c.ListMachineImages(ctx, scope)
+		if err != nil {
+			return nil, "", err
+		}
+		return images.Images, "", nil
+	}, filter.MatchMachineImage)
+}
+
+// AllHttpPortMappings iterates over the HTTP port mappings of a sandbox.
+func AllHttpPortMappings(ctx context.Context, c Client, sandboxId string) iter.Seq2[HttpMappingResponseDto, error] {
+	return paginate(ctx, func(ctx context.Context, _ string) ([]HttpMappingResponseDto, string, error) {
+		mappings, err := c.ListHttpPortMappings(ctx, sandboxId)
+		return mappings, "", err
+	}, func(HttpMappingResponseDto) bool { return true })
+}
+
+// AllMcpServers iterates over the MCP servers selected by filter.
+func AllMcpServers(ctx context.Context, m Mcp, filter *ListFilter) iter.Seq2[McpServerResponseDto, error] {
+	return paginate(ctx, func(ctx context.Context, _ string) ([]McpServerResponseDto, string, error) {
+		servers, err := m.ListMcpServers(ctx)
+		return servers, "", err
+	}, filter.MatchMcpServer)
+}
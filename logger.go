@@ -39,6 +39,10 @@ type Logger interface {
 }
 
 // NewEmptyLogger returns a Logger that does not log anything.
+func NewEmptyLogger() Logger {
+	return emptyLogger{}
+}
+
 type emptyLogger struct{}
 
 func (emptyLogger) Debug(...interface{}) {}
@@ -81,7 +81,8 @@ type Client interface {
 	// ParseMobileUseModelTextOutput parses and validates mobile use actions from text input
 	ParseMobileUseModelTextOutput(ctx context.Context, modelType string, dto ParseTextRequestDto) (*MobileUseActionResponseDto, error)
 
-	// ExecuteSandboxAction performs a generic action on a sandbox
+	// ExecuteSandboxAction performs a generic action on a sandbox.
+	// The action is validated first, an invalid action returns an *ActionValidationError.
 	ExecuteSandboxAction(ctx context.Context, sandboxId string, dto ExecuteSandboxActionDto) (*SandboxActionResponseDto, error)
 
 	// CopyFilesWithSandbox copies files to/from the sandbox
@@ -156,6 +157,9 @@ type Config struct {
 
 	// HttpTransport allows customization of the HTTP transport layer, can be nil to use the default transport
 	HttpTransport http.RoundTripper
+
+	// Tracker records every resource created through the client for later cleanup, can be nil to disable tracking
+	Tracker *ResourceTracker
 }
 
 // NewConfig creates a new Config instance with default values and environment variables.
@@ -0,0 +1,150 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	minMachineImageBackoff = 2 * time.Second
+	maxMachineImageBackoff = 30 * time.Second
+)
+
+var ErrMachineImageNotFound = errors.New("machine image not found")
+
+// ErrMachineImageFailed is returned by WaitForMachineImage when the image ends up in the ERROR status.
+type ErrMachineImageFailed struct {
+	Image MachineImageResponseDto
+}
+
+func (e *ErrMachineImageFailed) Error() string {
+	return fmt.Sprintf("machine image %s (%s) failed to build", e.Image.Id, e.Image.Name)
+}
+
+// WaitForMachineImage polls ListMachineImages until the image is READY and returns it.
+//
+//	The poll interval starts at 2 seconds and doubles up to 30 seconds. If the image
+//	ends up in the ERROR status the error is an *ErrMachineImageFailed, if it disappears
+//	the error wraps ErrMachineImageNotFound.
+func WaitForMachineImage(ctx context.Context, c Client, imageId string) (*MachineImageResponseDto, error) {
+	backoff := minMachineImageBackoff
+	for {
+		images, err := c.ListMachineImages(ctx, "org")
+		if err != nil {
+			return nil, err
+		}
+		idx := slices.IndexFunc(images.Images, func(image MachineImagesResponseDtoImages) bool {
+			return image.Id == imageId
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrMachineImageNotFound, imageId)
+		}
+		image := images.Images[idx]
+		switch image.GetStatus() {
+		case MachineImageReady:
+			return &image, nil
+		case MachineImageError:
+			return nil, &ErrMachineImageFailed{Image: image}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxMachineImageBackoff)
+	}
+}
+
+// ImageEvictionPolicy selects the org-scoped machine images that PreflightMachineImageQuota
+// may delete to free quota. Images are evicted oldest first and images that are still
+// being created are never evicted.
+type ImageEvictionPolicy struct {
+	// NamePrefix restricts eviction to images whose name starts with the prefix
+	NamePrefix string
+
+	// MinAge protects images younger than this
+	MinAge time.Duration
+
+	// Allowlist holds image IDs that are never evicted
+	Allowlist []string
+}
+
+func (p *ImageEvictionPolicy) evictable(image MachineImagesResponseDtoImages, now time.Time) bool {
+	return strings.EqualFold(image.Scope, "org") &&
+		image.GetStatus() != MachineImageCreating &&
+		strings.HasPrefix(image.Name, p.NamePrefix) &&
+		now.Sub(image.CreatedAt) >= p.MinAge &&
+		!slices.Contains(p.Allowlist, image.Id)
+}
+
+// PreflightMachineImageQuota checks that one more machine image can be created.
+//
+//	When the quota is full and policy is nil, the error is an *ErrMachineImageQuotaExceeded.
+//	Otherwise the oldest images selected by the policy are deleted until one slot is free,
+//	and the evicted images are returned.
+func PreflightMachineImageQuota(ctx context.Context, c Client, policy *ImageEvictionPolicy) ([]MachineImageResponseDto, error) {
+	images, err := c.ListMachineImages(ctx, "org")
+	if err != nil {
+		return nil, err
+	}
+	quota := images.Quota
+	if quota.Limit <= 0 || quota.Used < quota.Limit {
+		return nil, nil
+	}
+	if policy == nil {
+		return nil, &ErrMachineImageQuotaExceeded{Used: quota.Used, Limit: quota.Limit}
+	}
+
+	now := time.Now()
+	var candidates []MachineImageResponseDto
+	for _, image := range images.Images {
+		if policy.evictable(image, now) {
+			candidates = append(candidates, image)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b MachineImageResponseDto) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	need := int(quota.Used-quota.Limit) + 1
+	if len(candidates) < need {
+		return nil, &ErrMachineImageQuotaExceeded{Used: quota.Used, Limit: quota.Limit}
+	}
+
+	var evicted []MachineImageResponseDto
+	for _, image := range candidates[:need] {
+		loggerOf(c).Infof("evicting machine image %s (%s) to free quota", image.Id, image.Name)
+		if err := c.DeleteMachineImage(ctx, image.Id); err != nil {
+			return evicted, err
+		}
+		evicted = append(evicted, image)
+	}
+	return evicted, nil
+}
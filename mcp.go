@@ -60,6 +60,7 @@ func (m *mcpClient) CreateMcpServer(ctx context.Context, dto CreateMcpServerDto)
 	if err := tryToGetDto[McpServerResponseDto](resp, &mcpServer); err != nil {
 		return nil, err
 	}
+	m.client.config.Tracker.created(ResourceMcpServer, "", mcpServer.Id)
 
 	return &mcpServer, nil
 }
@@ -90,7 +91,11 @@ func (m *mcpClient) DeleteMcpServer(ctx context.Context, mcpServerId string) err
 		return err
 	}
 
-	return tryToGetDto[any](resp, nil)
+	if err := tryToGetDto[any](resp, nil); err != nil {
+		return err
+	}
+	m.client.config.Tracker.deleted(ResourceMcpServer, "", mcpServerId)
+	return nil
 }
 
 // SetMcpServerToSandbox sets the specified MCP server to the given Sandbox.
@@ -30,6 +30,18 @@ import (
 //
 // Implementations:
 //
+// # TouchTapAction
+//
+// # TouchDragAction
+//
+// # TouchSwipeAction
+//
+// # TouchLongPressAction
+//
+// # AndroidHomeAction
+//
+// # AndroidBackAction
+//
 // # MobileTapAction
 //
 // # MobileDoubleTapAction
@@ -55,6 +67,9 @@ type MobileUseActionDtoActionOneOf interface {
 	MarshalJSON() ([]byte, error)
 	UnmarshalJSON(src []byte) error
 
+	// Validate checks the fields of the action before it is sent
+	Validate() error
+
 	// __internalMobileUseActionDtoActionOneOf is a dummy method to prevent external implementations
 	__internalMobileUseActionDtoActionOneOf()
 	// _internalSandboxUseActionDtoActionOneOf is a dummy method to prevent external implementations
@@ -69,6 +84,107 @@ func TryUnmarshalToMobileUseActionDtoActionOneOf(data map[string]any) (MobileUse
 	return rawMessageToMobileUseActionDtoActionOneOf(marshal)
 }
 
+// TouchTapAction taps the screen, serialized as the spec's touch:tap.
+type TouchTapAction struct {
+	Type   string  `json:"type"` // set to touch:tap
+	X      Length  `json:"x"`
+	Y      Length  `json:"y"`
+	CallId *string `json:"callId,omitempty"`
+}
+
+func NewTouchTapAction(x Length, y Length) *TouchTapAction {
+	return &TouchTapAction{
+		Type: "touch:tap",
+		X:    x,
+		Y:    y,
+	}
+}
+
+// TouchDragAction drags from one point to another, serialized as the spec's touch:drag.
+type TouchDragAction struct {
+	Type   string  `json:"type"` // set to touch:drag
+	StartX Length  `json:"startX"`
+	StartY Length  `json:"startY"`
+	EndX   Length  `json:"endX"`
+	EndY   Length  `json:"endY"`
+	CallId *string `json:"callId,omitempty"`
+}
+
+func NewTouchDragAction(startX, startY, endX, endY Length) *TouchDragAction {
+	return &TouchDragAction{
+		Type:   "touch:drag",
+		StartX: startX,
+		StartY: startY,
+		EndX:   endX,
+		EndY:   endY,
+	}
+}
+
+// TouchSwipeAction swipes from a point in a direction, serialized as the spec's touch:swipe.
+type TouchSwipeAction struct {
+	Type      string  `json:"type"` // set to touch:swipe
+	X         Length  `json:"x"`
+	Y         Length  `json:"y"`
+	Direction string  `json:"direction"` // one of "up", "down", "left", "right"
+	Distance  Length  `json:"distance"`
+	CallId    *string `json:"callId,omitempty"`
+}
+
+func NewTouchSwipeAction(x, y Length, direction string, distance Length) *TouchSwipeAction {
+	return &TouchSwipeAction{
+		Type:      "touch:swipe",
+		X:         x,
+		Y:         y,
+		Direction: direction,
+		Distance:  distance,
+	}
+}
+
+// TouchLongPressAction presses a point for a while, serialized as the spec's touch:longPress.
+type TouchLongPressAction struct {
+	Type     string  `json:"type"` // set to touch:longPress
+	X        Length  `json:"x"`
+	Y        Length  `json:"y"`
+	Duration int     `json:"duration"` // Duration in milliseconds
+	CallId   *string `json:"callId,omitempty"`
+}
+
+func NewTouchLongPressAction(x, y Length, duration int) *TouchLongPressAction {
+	return &TouchLongPressAction{
+		Type:     "touch:longPress",
+		X:        x,
+		Y:        y,
+		Duration: duration,
+	}
+}
+
+// AndroidHomeAction presses the home button, serialized as the spec's android:home.
+type AndroidHomeAction struct {
+	Type   string  `json:"type"` // set to android:home
+	CallId *string `json:"callId,omitempty"`
+}
+
+func NewAndroidHomeAction() *AndroidHomeAction {
+	return &AndroidHomeAction{
+		Type: "android:home",
+	}
+}
+
+// AndroidBackAction presses the back button, serialized as the spec's android:back.
+type AndroidBackAction struct {
+	Type   string  `json:"type"` // set to android:back
+	CallId *string `json:"callId,omitempty"`
+}
+
+func NewAndroidBackAction() *AndroidBackAction {
+	return &AndroidBackAction{
+		Type: "android:back",
+	}
+}
+
+// MobileTapAction is serialized as mobile:tap, which is not part of the published spec.
+//
+//	Deprecated: Use TouchTapAction instead.
 type MobileTapAction struct {
 	Type   string  `json:"type"` // set to mobile:tap
 	X      Length  `json:"x"`
@@ -99,6 +215,9 @@ func NewMobileDoubleTapAction(x Length, y Length) *MobileDoubleTapAction {
 	}
 }
 
+// MobileSwipeAction is serialized as mobile:swipe, which is not part of the published spec.
+//
+//	Deprecated: Use TouchDragAction for point to point gestures or TouchSwipeAction instead.
 type MobileSwipeAction struct {
 	Type     string  `json:"type"` // set to mobile:swipe
 	StartX   Length  `json:"startX"`
@@ -146,6 +265,9 @@ func NewMobileHotkeyAction(key string) *MobileHotkeyAction {
 	}
 }
 
+// MobileHomeAction is serialized as mobile:home, which is not part of the published spec.
+//
+//	Deprecated: Use AndroidHomeAction instead.
 type MobileHomeAction struct {
 	Type   string  `json:"type"` // set to mobile:home
 	CallId *string `json:"callId,omitempty"`
@@ -157,6 +279,9 @@ func NewMobileHomeAction() *MobileHomeAction {
 	}
 }
 
+// MobileBackAction is serialized as mobile:back, which is not part of the published spec.
+//
+//	Deprecated: Use AndroidBackAction instead.
 type MobileBackAction struct {
 	Type   string  `json:"type"` // set to mobile:back
 	CallId *string `json:"callId,omitempty"`
@@ -28,6 +28,258 @@ import (
 	"github.com/lybic/lybic-sdk-go/pkg/json"
 )
 
+func (m TouchTapAction) MarshalJSON() ([]byte, error) {
+	toSerialize := map[string]interface{}{
+		"type": "touch:tap",
+		"x":    m.X,
+		"y":    m.Y,
+	}
+	if m.CallId != nil {
+		toSerialize["callId"] = *m.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (m *TouchTapAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		m.Type = v
+	}
+	if v, ok := value["x"]; ok {
+		x, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.X = x
+	}
+	if v, ok := value["y"]; ok {
+		y, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.Y = y
+	}
+	if v, ok := value["callId"].(string); ok {
+		m.CallId = &v
+	}
+	return nil
+}
+
+func (TouchTapAction) __internalMobileUseActionDtoActionOneOf() {}
+
+func (m TouchDragAction) MarshalJSON() ([]byte, error) {
+	toSerialize := map[string]interface{}{
+		"type":   "touch:drag",
+		"startX": m.StartX,
+		"startY": m.StartY,
+		"endX":   m.EndX,
+		"endY":   m.EndY,
+	}
+	if m.CallId != nil {
+		toSerialize["callId"] = *m.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (m *TouchDragAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		m.Type = v
+	}
+	if v, ok := value["startX"]; ok {
+		startX, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.StartX = startX
+	}
+	if v, ok := value["startY"]; ok {
+		startY, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.StartY = startY
+	}
+	if v, ok := value["endX"]; ok {
+		endX, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.EndX = endX
+	}
+	if v, ok := value["endY"]; ok {
+		endY, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.EndY = endY
+	}
+	if v, ok := value["callId"].(string); ok {
+		m.CallId = &v
+	}
+	return nil
+}
+
+func (TouchDragAction) __internalMobileUseActionDtoActionOneOf() {}
+
+func (m TouchSwipeAction) MarshalJSON() ([]byte, error) {
+	toSerialize := map[string]interface{}{
+		"type":      "touch:swipe",
+		"x":         m.X,
+		"y":         m.Y,
+		"distance":  m.Distance,
+		"direction": m.Direction,
+	}
+	if m.CallId != nil {
+		toSerialize["callId"] = *m.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (m *TouchSwipeAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		m.Type = v
+	}
+	if v, ok := value["x"]; ok {
+		x, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.X = x
+	}
+	if v, ok := value["y"]; ok {
+		y, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.Y = y
+	}
+	if v, ok := value["distance"]; ok {
+		distance, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.Distance = distance
+	}
+	if v, ok := value["direction"].(string); ok {
+		m.Direction = v
+	}
+	if v, ok := value["callId"].(string); ok {
+		m.CallId = &v
+	}
+	return nil
+}
+
+func (TouchSwipeAction) __internalMobileUseActionDtoActionOneOf() {}
+
+func (m TouchLongPressAction) MarshalJSON() ([]byte, error) {
+	toSerialize := map[string]interface{}{
+		"type":     "touch:longPress",
+		"x":        m.X,
+		"y":        m.Y,
+		"duration": m.Duration,
+	}
+	if m.CallId != nil {
+		toSerialize["callId"] = *m.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (m *TouchLongPressAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		m.Type = v
+	}
+	if v, ok := value["x"]; ok {
+		x, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.X = x
+	}
+	if v, ok := value["y"]; ok {
+		y, err := unmarshalLength(v)
+		if err != nil {
+			return err
+		}
+		m.Y = y
+	}
+	if v, ok := value["duration"].(float64); ok {
+		m.Duration = int(v)
+	}
+	if v, ok := value["callId"].(string); ok {
+		m.CallId = &v
+	}
+	return nil
+}
+
+func (TouchLongPressAction) __internalMobileUseActionDtoActionOneOf() {}
+
+func (m AndroidHomeAction) MarshalJSON() ([]byte, error) {
+	toSerialize := map[string]interface{}{
+		"type": "android:home",
+	}
+	if m.CallId != nil {
+		toSerialize["callId"] = *m.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (m *AndroidHomeAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		m.Type = v
+	}
+	if v, ok := value["callId"].(string); ok {
+		m.CallId = &v
+	}
+	return nil
+}
+
+func (AndroidHomeAction) __internalMobileUseActionDtoActionOneOf() {}
+
+func (m AndroidBackAction) MarshalJSON() ([]byte, error) {
+	toSerialize := map[string]interface{}{
+		"type": "android:back",
+	}
+	if m.CallId != nil {
+		toSerialize["callId"] = *m.CallId
+	}
+	return json.Marshal(toSerialize)
+}
+
+func (m *AndroidBackAction) UnmarshalJSON(src []byte) error {
+	var value map[string]interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return err
+	}
+	if v, ok := value["type"].(string); ok {
+		m.Type = v
+	}
+	if v, ok := value["callId"].(string); ok {
+		m.CallId = &v
+	}
+	return nil
+}
+
+func (AndroidBackAction) __internalMobileUseActionDtoActionOneOf() {}
+
 func (m MobileTapAction) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{
 		"type": "mobile:tap",
@@ -415,7 +667,20 @@ func rawMessageToMobileUseActionDtoActionOneOf(rawAction json.RawMessage) (Mobil
 	}
 
 	var action MobileUseActionDtoActionOneOf
+	// the spec's touch:* and android:* names and the legacy mobile:* names are both accepted
 	switch base.Type {
+	case "touch:tap":
+		action = &TouchTapAction{}
+	case "touch:drag":
+		action = &TouchDragAction{}
+	case "touch:swipe":
+		action = &TouchSwipeAction{}
+	case "touch:longPress":
+		action = &TouchLongPressAction{}
+	case "android:home":
+		action = &AndroidHomeAction{}
+	case "android:back":
+		action = &AndroidBackAction{}
 	case "mobile:tap":
 		action = &MobileTapAction{}
 	case "mobile:doubleTap":
@@ -483,6 +748,13 @@ func (m *MobileUseActionResponseDto) UnmarshalJSON(data []byte) error {
 
 func (MobileTapAction) _internalSandboxUseActionDtoActionOneOf() {}
 
+func (TouchTapAction) _internalSandboxUseActionDtoActionOneOf()       {}
+func (TouchDragAction) _internalSandboxUseActionDtoActionOneOf()      {}
+func (TouchSwipeAction) _internalSandboxUseActionDtoActionOneOf()     {}
+func (TouchLongPressAction) _internalSandboxUseActionDtoActionOneOf() {}
+func (AndroidHomeAction) _internalSandboxUseActionDtoActionOneOf()    {}
+func (AndroidBackAction) _internalSandboxUseActionDtoActionOneOf()    {}
+
 func (MobileDoubleTapAction) _internalSandboxUseActionDtoActionOneOf()  {}
 func (MobileSwipeAction) _internalSandboxUseActionDtoActionOneOf()      {}
 func (MobileTypeAction) _internalSandboxUseActionDtoActionOneOf()       {}
@@ -0,0 +1,138 @@
+package lybic
+
+import (
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+// specActionKeys returns the properties of the action schema with the given type in
+// docs/openapi.json, sorted.
+func specActionKeys(t *testing.T, actionType string) []string {
+	t.Helper()
+	data, err := os.ReadFile("docs/openapi.json")
+	if err != nil {
+		t.Fatalf("failed to read the spec: %v", err)
+	}
+	var spec any
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("failed to parse the spec: %v", err)
+	}
+
+	var keys []string
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if props, ok := node["properties"].(map[string]any); ok && keys == nil {
+				if typ, ok := props["type"].(map[string]any); ok {
+					enum, _ := typ["enum"].([]any)
+					if typ["const"] == actionType || slices.Contains(enum, any(actionType)) {
+						for key := range props {
+							keys = append(keys, key)
+						}
+						slices.Sort(keys)
+						return
+					}
+				}
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(spec)
+	if keys == nil {
+		t.Fatalf("no schema with type %q in the spec", actionType)
+	}
+	return keys
+}
+
+func TestMobileActionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		action SandboxUseActionDtoActionOneOf
+	}{
+		{"touch:tap", NewTouchTapAction(NewPixelLength(10), NewFractionalLength(1, 2))},
+		{"touch:drag", NewTouchDragAction(NewPixelLength(1), NewPixelLength(2), NewPixelLength(3), NewFractionalLength(3, 4))},
+		{"touch:swipe", NewTouchSwipeAction(NewPixelLength(5), NewPixelLength(6), "up", NewPixelLength(300))},
+		{"touch:longPress", NewTouchLongPressAction(NewPixelLength(7), NewPixelLength(8), 1500)},
+		{"android:home", NewAndroidHomeAction()},
+		{"android:back", NewAndroidBackAction()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(ExecuteSandboxActionDto{Action: tt.action})
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			var wire struct {
+				Action map[string]any `json:"action"`
+			}
+			if err := json.Unmarshal(data, &wire); err != nil {
+				t.Fatalf("failed to decode %s: %v", data, err)
+			}
+			if wire.Action["type"] != tt.name {
+				t.Errorf("type = %v, want %s", wire.Action["type"], tt.name)
+			}
+			var keys []string
+			for key := range wire.Action {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			if want := specActionKeys(t, tt.name); !slices.Equal(keys, want) {
+				t.Errorf("keys = %v, want %v from the spec", keys, want)
+			}
+
+			var decoded ExecuteSandboxActionDto
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(decoded.Action, tt.action) {
+				t.Errorf("round trip = %#v, want %#v", decoded.Action, tt.action)
+			}
+		})
+	}
+}
+
+func TestLegacyMobileActionDecode(t *testing.T) {
+	px := `{"type":"px","value":4}`
+	tests := []struct {
+		json string
+		want SandboxUseActionDtoActionOneOf
+	}{
+		{`{"type":"mobile:tap","x":` + px + `,"y":` + px + `}`, NewMobileTapAction(NewPixelLength(4), NewPixelLength(4))},
+		{`{"type":"mobile:doubleTap","x":` + px + `,"y":` + px + `}`, NewMobileDoubleTapAction(NewPixelLength(4), NewPixelLength(4))},
+		{`{"type":"mobile:swipe","startX":` + px + `,"startY":` + px + `,"endX":` + px + `,"endY":` + px + `,"duration":200}`,
+			NewMobileSwipeAction(NewPixelLength(4), NewPixelLength(4), NewPixelLength(4), NewPixelLength(4), 200)},
+		{`{"type":"mobile:type","content":"hi"}`, NewMobileTypeAction("hi")},
+		{`{"type":"mobile:hotkey","key":"KEYCODE_ENTER"}`, NewMobileHotkeyAction("KEYCODE_ENTER")},
+		{`{"type":"mobile:home"}`, NewMobileHomeAction()},
+		{`{"type":"mobile:back"}`, NewMobileBackAction()},
+		{`{"type":"mobile:screenshot"}`, NewMobileScreenshotAction()},
+		{`{"type":"mobile:wait","duration":500}`, NewMobileWaitAction(500)},
+		{`{"type":"mobile:finished"}`, NewMobileFinishedAction()},
+		{`{"type":"mobile:failed"}`, NewMobileFailedAction()},
+	}
+	for _, tt := range tests {
+		name, _, _ := strings.Cut(strings.TrimPrefix(tt.json, `{"type":"`), `"`)
+		t.Run(name, func(t *testing.T) {
+			var decoded ExecuteSandboxActionDto
+			if err := json.Unmarshal([]byte(`{"action":`+tt.json+`}`), &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(decoded.Action, tt.want) {
+				t.Errorf("decoded = %#v, want %#v", decoded.Action, tt.want)
+			}
+		})
+	}
+}
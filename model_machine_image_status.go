@@ -0,0 +1,14 @@
+package lybic
+
+type MachineImageStatus string
+
+const (
+	MachineImageCreating MachineImageStatus = "CREATING"
+	MachineImageReady    MachineImageStatus = "READY"
+	MachineImageError    MachineImageStatus = "ERROR"
+)
+
+// GetStatus returns the typed Status field value.
+func (o MachineImagesResponseDtoImages) GetStatus() MachineImageStatus {
+	return MachineImageStatus(o.Status)
+}
@@ -12,3 +12,19 @@ const (
 type SandboxStatusDto struct {
 	Status SandboxStatus `json:"status"`
 }
+
+// GetStatus returns the typed Status field value if set, empty otherwise.
+func (o CreateSandboxResponseDto) GetStatus() SandboxStatus {
+	if o.Status == nil {
+		return ""
+	}
+	return SandboxStatus(*o.Status)
+}
+
+// GetStatus returns the typed Status field value if set, empty otherwise.
+func (o GetSandboxResponseDtoSandbox) GetStatus() SandboxStatus {
+	if o.Status == nil {
+		return ""
+	}
+	return SandboxStatus(*o.Status)
+}
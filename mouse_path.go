@@ -0,0 +1,247 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ErrDragPathTooShort is returned by DragPath when less than two waypoints are given.
+var ErrDragPathTooShort = errors.New("drag path requires at least two waypoints")
+
+const (
+	defaultPathSteps     = 20
+	defaultPathCurvature = 0.3
+)
+
+// PathKind is the shape of a MousePath.
+type PathKind int
+
+const (
+	// PathLinear moves along a straight line at constant speed
+	PathLinear PathKind = iota
+	// PathEased moves along a straight line, accelerating then slowing down
+	PathEased
+	// PathBezier moves along a random cubic Bézier curve with eased speed
+	PathBezier
+)
+
+// PathPoint is a point of the screen in pixels.
+type PathPoint struct {
+	X int
+	Y int
+}
+
+// MousePath expands a mouse move into intermediate points, so that the cursor travels like
+// a human hand instead of jumping. The same Seed always gives the same path.
+type MousePath struct {
+	Kind PathKind
+
+	// Steps is the number of moves, defaults to 20
+	Steps int
+
+	// Duration is the total time of the move, spread as waits between the moves;
+	// no waits are added if zero
+	Duration time.Duration
+
+	// Curvature is the largest distance of the Bézier control points from the straight
+	// line, as a fraction of its length, defaults to 0.3
+	Curvature float64
+
+	// Jitter is the largest random offset in pixels added to each intermediate point
+	Jitter float64
+
+	Seed int64
+}
+
+// Points returns the points of the path from one point to another, excluding the start and
+// ending exactly at the destination. Points are never negative.
+func (p MousePath) Points(from, to PathPoint) []PathPoint {
+	steps := p.Steps
+	if steps <= 0 {
+		steps = defaultPathSteps
+	}
+	rng := rand.New(rand.NewSource(p.Seed))
+
+	x0, y0 := float64(from.X), float64(from.Y)
+	x3, y3 := float64(to.X), float64(to.Y)
+	// control points of the Bézier curve, on the straight line unless the path is curved
+	x1, y1 := x0+(x3-x0)/3, y0+(y3-y0)/3
+	x2, y2 := x0+(x3-x0)*2/3, y0+(y3-y0)*2/3
+	if p.Kind == PathBezier {
+		curvature := p.Curvature
+		if curvature <= 0 {
+			curvature = defaultPathCurvature
+		}
+		// offset both control points perpendicular to the line
+		nx, ny := -(y3 - y0), x3-x0
+		o1 := (rng.Float64()*2 - 1) * curvature
+		o2 := (rng.Float64()*2 - 1) * curvature
+		x1, y1 = x1+nx*o1, y1+ny*o1
+		x2, y2 = x2+nx*o2, y2+ny*o2
+	}
+
+	points := make([]PathPoint, steps)
+	for i := 1; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		if p.Kind != PathLinear {
+			t = easeInOutCubic(t)
+		}
+		u := 1 - t
+		x := u*u*u*x0 + 3*u*u*t*x1 + 3*u*t*t*x2 + t*t*t*x3
+		y := u*u*u*y0 + 3*u*u*t*y1 + 3*u*t*t*y2 + t*t*t*y3
+		if i < steps && p.Jitter > 0 {
+			x += (rng.Float64()*2 - 1) * p.Jitter
+			y += (rng.Float64()*2 - 1) * p.Jitter
+		}
+		// curves and jitter must not leave the screen on the top or left edge
+		points[i-1] = PathPoint{X: int(math.Round(max(x, 0))), Y: int(math.Round(max(y, 0)))}
+	}
+	points[steps-1] = to
+	return points
+}
+
+// Moves returns the path as MouseMoveActions separated by WaitActions.
+func (p MousePath) Moves(from, to PathPoint) []SandboxUseActionDtoActionOneOf {
+	points := p.Points(from, to)
+	wait := p.stepWait(len(points))
+
+	actions := make([]SandboxUseActionDtoActionOneOf, 0, 2*len(points))
+	for i, point := range points {
+		if i > 0 && wait > 0 {
+			actions = append(actions, NewWaitAction(wait))
+		}
+		actions = append(actions, NewMouseMoveAction(NewPixelLength(point.X), NewPixelLength(point.Y)))
+	}
+	return actions
+}
+
+// stepWait spreads Duration over the given number of points, in milliseconds.
+func (p MousePath) stepWait(points int) int {
+	if p.Duration <= 0 || points == 0 {
+		return 0
+	}
+	return max(int(p.Duration.Milliseconds())/points, 1)
+}
+
+func easeInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// DragPathOptions configures DragPath.
+type DragPathOptions struct {
+	// Button is the mouse button flag combination, defaults to 1 (left)
+	Button int
+
+	// HoldKey is held from the first leg to the last, in xdotool key syntax, e.g. "shift"
+	// or "ctrl+alt"
+	HoldKey string
+
+	// Pause is the wait between two legs
+	Pause time.Duration
+
+	// Path, if set, expands each leg into the points of the path, the legs of a drag get
+	// consecutive seeds starting at Path.Seed
+	Path *MousePath
+}
+
+// DragPath returns the actions that emulate a drag through the waypoints in order. opts can
+// be nil.
+//
+//	The action API has no separate button press and release, the button is only held
+//	within a MouseDragAction, from its start to its end. A drag through several waypoints
+//	is therefore a chain of MouseDragActions, one per leg, and the button is released and
+//	pressed again at every waypoint; with Path it is released at every point of the path.
+//	Apps that track one uninterrupted press see several short drags. HoldKey is pressed
+//	with KeyDownAction before the first leg and released with KeyUpAction after the last,
+//	so that it stays held across the legs and pauses. If the actions stop before the end
+//	the keys stay pressed, run them inside HoldKeys instead of setting HoldKey to have them
+//	released on error.
+func DragPath(waypoints []PathPoint, opts *DragPathOptions) ([]SandboxUseActionDtoActionOneOf, error) {
+	if len(waypoints) < 2 {
+		return nil, ErrDragPathTooShort
+	}
+	var o DragPathOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Button == 0 {
+		o.Button = 1
+	}
+	var holdKeys []string
+	if o.HoldKey != "" {
+		chords, err := parseXdotoolKeys(o.HoldKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hold key: %w", err)
+		}
+		if len(chords) != 1 {
+			return nil, fmt.Errorf("hold key must be a single chord, got %q", o.HoldKey)
+		}
+		holdKeys = chords[0]
+	}
+
+	var actions []SandboxUseActionDtoActionOneOf
+	for _, key := range holdKeys {
+		actions = append(actions, NewKeyDownAction(key))
+	}
+	for i := 1; i < len(waypoints); i++ {
+		if i > 1 && o.Pause > 0 {
+			actions = append(actions, NewWaitAction(int(o.Pause.Milliseconds())))
+		}
+		actions = append(actions, o.leg(waypoints[i-1], waypoints[i], i-1)...)
+	}
+	for i := len(holdKeys) - 1; i >= 0; i-- {
+		actions = append(actions, NewKeyUpAction(holdKeys[i]))
+	}
+	return actions, nil
+}
+
+// leg returns the drags from one waypoint to the next, through the points of Path if set.
+func (o DragPathOptions) leg(from, to PathPoint, n int) []SandboxUseActionDtoActionOneOf {
+	points := []PathPoint{to}
+	wait := 0
+	if o.Path != nil {
+		path := *o.Path
+		path.Seed += int64(n)
+		points = path.Points(from, to)
+		wait = path.stepWait(len(points))
+	}
+
+	actions := make([]SandboxUseActionDtoActionOneOf, 0, 2*len(points))
+	for i, point := range points {
+		if i > 0 && wait > 0 {
+			actions = append(actions, NewWaitAction(wait))
+		}
+		actions = append(actions, NewMouseDragAction(NewPixelLength(from.X), NewPixelLength(from.Y),
+			NewPixelLength(point.X), NewPixelLength(point.Y), o.Button))
+		from = point
+	}
+	return actions
+}
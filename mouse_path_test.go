@@ -0,0 +1,124 @@
+package lybic
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMousePathPointsSeed(t *testing.T) {
+	from, to := PathPoint{X: 10, Y: 400}, PathPoint{X: 800, Y: 30}
+	for _, kind := range []PathKind{PathLinear, PathEased, PathBezier} {
+		path := MousePath{Kind: kind, Steps: 30, Jitter: 3, Seed: 42}
+		first := path.Points(from, to)
+		if second := path.Points(from, to); !slices.Equal(first, second) {
+			t.Errorf("kind %d: the same seed gave different points", kind)
+		}
+		if len(first) != 30 {
+			t.Errorf("kind %d: got %d points, want 30", kind, len(first))
+		}
+		if last := first[len(first)-1]; last != to {
+			t.Errorf("kind %d: last point = %v, want %v", kind, last, to)
+		}
+
+		path.Seed = 43
+		if other := path.Points(from, to); slices.Equal(first, other) {
+			t.Errorf("kind %d: seeds 42 and 43 gave the same points", kind)
+		}
+	}
+}
+
+func TestMousePathPointsNotNegative(t *testing.T) {
+	path := MousePath{Kind: PathBezier, Curvature: 2, Jitter: 20}
+	for seed := range int64(20) {
+		path.Seed = seed
+		for _, point := range path.Points(PathPoint{X: 0, Y: 0}, PathPoint{X: 5, Y: 0}) {
+			if point.X < 0 || point.Y < 0 {
+				t.Fatalf("seed %d: negative point %v", seed, point)
+			}
+		}
+	}
+}
+
+func TestDragPathWaypoints(t *testing.T) {
+	if _, err := DragPath([]PathPoint{{X: 1, Y: 1}}, nil); !errors.Is(err, ErrDragPathTooShort) {
+		t.Errorf("one waypoint: error = %v, want %v", err, ErrDragPathTooShort)
+	}
+	if _, err := DragPath([]PathPoint{{X: 1, Y: 1}, {X: 2, Y: 2}}, &DragPathOptions{HoldKey: "ctrl+"}); err == nil {
+		t.Error("invalid hold key: error = nil")
+	}
+
+	waypoints := []PathPoint{{X: 10, Y: 10}, {X: 100, Y: 50}, {X: 200, Y: 10}}
+	actions, err := DragPath(waypoints, &DragPathOptions{Button: 2, HoldKey: "ctrl+shift", Pause: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("DragPath() error = %v", err)
+	}
+	want := []SandboxUseActionDtoActionOneOf{
+		NewKeyDownAction("ctrl"),
+		NewKeyDownAction("shift"),
+		NewMouseDragAction(NewPixelLength(10), NewPixelLength(10), NewPixelLength(100), NewPixelLength(50), 2),
+		NewWaitAction(50),
+		NewMouseDragAction(NewPixelLength(100), NewPixelLength(50), NewPixelLength(200), NewPixelLength(10), 2),
+		NewKeyUpAction("shift"),
+		NewKeyUpAction("ctrl"),
+	}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("DragPath() = %#v, want %#v", actions, want)
+	}
+}
+
+func TestDragPathAlongPath(t *testing.T) {
+	waypoints := []PathPoint{{X: 10, Y: 10}, {X: 300, Y: 200}, {X: 20, Y: 400}}
+	opts := &DragPathOptions{Path: &MousePath{Kind: PathBezier, Steps: 5, Duration: 100 * time.Millisecond, Seed: 7}}
+	actions, err := DragPath(waypoints, opts)
+	if err != nil {
+		t.Fatalf("DragPath() error = %v", err)
+	}
+	again, _ := DragPath(waypoints, opts)
+	if !reflect.DeepEqual(actions, again) {
+		t.Error("the same seed gave different drags")
+	}
+
+	var drags []*MouseDragAction
+	for _, action := range actions {
+		switch action := action.(type) {
+		case *MouseDragAction:
+			drags = append(drags, action)
+		case *WaitAction:
+			if action.Duration != 20 {
+				t.Errorf("wait = %dms, want 20ms", action.Duration)
+			}
+		default:
+			t.Fatalf("unexpected action %T", action)
+		}
+	}
+	if len(drags) != 10 {
+		t.Fatalf("got %d drags, want 5 per leg", len(drags))
+	}
+	// the drags are chained: each one starts where the previous one ended
+	for i := 1; i < len(drags); i++ {
+		if !reflect.DeepEqual(drags[i].StartX, drags[i-1].EndX) || !reflect.DeepEqual(drags[i].StartY, drags[i-1].EndY) {
+			t.Errorf("drag %d starts at (%v, %v), want the end of drag %d", i, drags[i].StartX, drags[i].StartY, i-1)
+		}
+	}
+	if end := drags[4]; !reflect.DeepEqual(end.EndX, NewPixelLength(300)) || !reflect.DeepEqual(end.EndY, NewPixelLength(200)) {
+		t.Errorf("first leg ends at (%v, %v), want the second waypoint", end.EndX, end.EndY)
+	}
+}
+
+func TestMouseDragActionButton(t *testing.T) {
+	drag := NewMouseDragAction(NewPixelLength(1), NewPixelLength(2), NewPixelLength(3), NewPixelLength(4), 2)
+	data, err := drag.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var decoded MouseDragAction
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if decoded.Button != 2 {
+		t.Errorf("button = %d after a round trip of %s, want 2", decoded.Button, data)
+	}
+}
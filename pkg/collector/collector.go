@@ -0,0 +1,233 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace       = "lybic"
+	defaultInterval = time.Minute
+)
+
+// Options configures a Collector.
+type Options struct {
+	// Interval is the time between two refreshes, defaults to 1 minute
+	Interval time.Duration
+
+	// Mcp is used to count MCP servers, can be nil to skip them
+	Mcp lybic.Mcp
+
+	// SandboxExpiry exports lybic_sandbox_expiry_minutes with one series per sandbox.
+	// It is off by default, since every sandbox ever created adds a series.
+	SandboxExpiry bool
+}
+
+var (
+	sandboxesDesc = prometheus.NewDesc(namespace+"_sandboxes",
+		"Number of sandboxes.", []string{"status", "shape", "project"}, nil)
+	sandboxExpiryDesc = prometheus.NewDesc(namespace+"_sandbox_expiry_minutes",
+		"Minutes until the sandbox expires.", []string{"sandbox_id", "name", "project"}, nil)
+	imageQuotaUsedDesc = prometheus.NewDesc(namespace+"_machine_image_quota_used",
+		"Number of machine images counted against the organization quota.", nil, nil)
+	imageQuotaLimitDesc = prometheus.NewDesc(namespace+"_machine_image_quota_limit",
+		"Machine image quota of the organization.", nil, nil)
+	mcpServersDesc = prometheus.NewDesc(namespace+"_mcp_servers",
+		"Number of MCP servers.", []string{"project"}, nil)
+	httpMappingsDesc = prometheus.NewDesc(namespace+"_http_port_mappings",
+		"Number of HTTP port mappings of running sandboxes.", []string{"project"}, nil)
+	upDesc = prometheus.NewDesc(namespace+"_up",
+		"Whether the last refresh succeeded.", nil, nil)
+	lastRefreshDesc = prometheus.NewDesc(namespace+"_last_refresh_timestamp_seconds",
+		"Unix time of the last successful refresh.", nil, nil)
+)
+
+type sandboxKey struct {
+	status, shape, project string
+}
+
+type sandboxExpiry struct {
+	id, name, project string
+	minutes           float64
+}
+
+// snapshot is the state served to scrapes.
+type snapshot struct {
+	sandboxes    map[sandboxKey]int
+	expiries     []sandboxExpiry
+	quotaUsed    float64
+	quotaLimit   float64
+	mcpServers   map[string]int
+	httpMappings map[string]int
+	refreshedAt  time.Time
+}
+
+// Collector is a prometheus.Collector for the resources of a Lybic organization.
+type Collector struct {
+	client lybic.Client
+	opts   Options
+	logger lybic.Logger
+
+	mu       sync.RWMutex
+	snapshot *snapshot
+	up       bool
+}
+
+// New creates a collector that reads through the client. opts can be nil.
+func New(client lybic.Client, opts *Options) *Collector {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = defaultInterval
+	}
+	c := &Collector{
+		client: client,
+		opts:   o,
+		logger: lybic.NewEmptyLogger(),
+	}
+	if config := client.GetConfig(); config != nil && config.Logger != nil {
+		c.logger = config.Logger
+	}
+	return c
+}
+
+// Run refreshes the snapshot immediately and then at every interval until ctx is done.
+func (c *Collector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Warnf("collector: refresh failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh reads the resources of the organization and replaces the snapshot.
+//
+//	On failure the previous snapshot is kept and lybic_up is set to 0.
+func (c *Collector) Refresh(ctx context.Context) error {
+	s, err := c.collect(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.up = err == nil
+	if err == nil {
+		c.snapshot = s
+	}
+	return err
+}
+
+func (c *Collector) collect(ctx context.Context) (*snapshot, error) {
+	now := time.Now()
+	s := &snapshot{
+		sandboxes:    make(map[sandboxKey]int),
+		mcpServers:   make(map[string]int),
+		httpMappings: make(map[string]int),
+		refreshedAt:  now,
+	}
+
+	sandboxes, err := c.client.ListSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sandbox := range sandboxes {
+		status := sandbox.GetStatus()
+		s.sandboxes[sandboxKey{string(status), sandbox.ShapeName, sandbox.ProjectId}]++
+		if status != lybic.SandboxRunning && status != lybic.SandboxPending {
+			continue
+		}
+		if c.opts.SandboxExpiry {
+			s.expiries = append(s.expiries, sandboxExpiry{
+				id:      sandbox.Id,
+				name:    sandbox.Name,
+				project: sandbox.ProjectId,
+				minutes: max(sandbox.ExpiresAt.Sub(now).Minutes(), 0),
+			})
+		}
+		if status == lybic.SandboxRunning {
+			mappings, err := c.client.ListHttpPortMappings(ctx, sandbox.Id)
+			if err != nil {
+				return nil, err
+			}
+			s.httpMappings[sandbox.ProjectId] += len(mappings)
+		}
+	}
+
+	images, err := c.client.ListMachineImages(ctx, "org")
+	if err != nil {
+		return nil, err
+	}
+	s.quotaUsed = float64(images.Quota.Used)
+	s.quotaLimit = float64(images.Quota.Limit)
+
+	if c.opts.Mcp != nil {
+		servers, err := c.opts.Mcp.ListMcpServers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, server := range servers {
+			s.mcpServers[server.ProjectId]++
+		}
+	}
+	return s, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sandboxesDesc
+	if c.opts.SandboxExpiry {
+		ch <- sandboxExpiryDesc
+	}
+	ch <- imageQuotaUsedDesc
+	ch <- imageQuotaLimitDesc
+	ch <- mcpServersDesc
+	ch <- httpMappingsDesc
+	ch <- upDesc
+	ch <- lastRefreshDesc
+}
+
+// Collect implements prometheus.Collector, it serves the last snapshot.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	up := 0.0
+	if c.up {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up)
+
+	s := c.snapshot
+	if s == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(lastRefreshDesc, prometheus.GaugeValue, float64(s.refreshedAt.Unix()))
+	for key, n := range s.sandboxes {
+		ch <- prometheus.MustNewConstMetric(sandboxesDesc, prometheus.GaugeValue, float64(n), key.status, key.shape, key.project)
+	}
+	for _, e := range s.expiries {
+		ch <- prometheus.MustNewConstMetric(sandboxExpiryDesc, prometheus.GaugeValue, e.minutes, e.id, e.name, e.project)
+	}
+	ch <- prometheus.MustNewConstMetric(imageQuotaUsedDesc, prometheus.GaugeValue, s.quotaUsed)
+	ch <- prometheus.MustNewConstMetric(imageQuotaLimitDesc, prometheus.GaugeValue, s.quotaLimit)
+	if c.opts.Mcp != nil {
+		for project, n := range s.mcpServers {
+			ch <- prometheus.MustNewConstMetric(mcpServersDesc, prometheus.GaugeValue, float64(n), project)
+		}
+	}
+	for project, n := range s.httpMappings {
+		ch <- prometheus.MustNewConstMetric(httpMappingsDesc, prometheus.GaugeValue, float64(n), project)
+	}
+}
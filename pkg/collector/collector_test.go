@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newStandIn serves the endpoints read by the collector from canned responses.
+func newStandIn(t *testing.T, imagesStatus int) *httptest.Server {
+	t.Helper()
+	expiresAt := time.Now().Add(90 * time.Minute).UTC().Format(time.RFC3339)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/orgs/org/sandboxes", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"sbx-1","name":"one","projectId":"p1","shapeName":"small","status":"RUNNING","expiresAt":"` + expiresAt + `"},
+			{"id":"sbx-2","name":"two","projectId":"p1","shapeName":"small","status":"RUNNING","expiresAt":"` + expiresAt + `"},
+			{"id":"sbx-3","name":"three","projectId":"p2","shapeName":"large","status":"STOPPED","expiresAt":"` + expiresAt + `"}
+		]`))
+	})
+	mux.HandleFunc("GET /api/orgs/org/sandboxes/{id}/mappings", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"domain":"a","targetEndpoint":"127.0.0.1:80"}]`))
+	})
+	mux.HandleFunc("GET /api/orgs/org/machine-images", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(imagesStatus)
+		_, _ = w.Write([]byte(`{"images":[],"quota":{"used":3,"limit":10}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestCollector(t *testing.T, endpoint string, opts *Options) *Collector {
+	t.Helper()
+	client, err := lybic.NewClient(&lybic.Config{OrgId: "org", ApiKey: "key", Endpoint: endpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(client, opts)
+}
+
+func TestCollectorRefresh(t *testing.T) {
+	server := newStandIn(t, http.StatusOK)
+	c := newTestCollector(t, server.URL, nil)
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	expected := `
+# HELP lybic_http_port_mappings Number of HTTP port mappings of running sandboxes.
+# TYPE lybic_http_port_mappings gauge
+lybic_http_port_mappings{project="p1"} 2
+# HELP lybic_machine_image_quota_limit Machine image quota of the organization.
+# TYPE lybic_machine_image_quota_limit gauge
+lybic_machine_image_quota_limit 10
+# HELP lybic_machine_image_quota_used Number of machine images counted against the organization quota.
+# TYPE lybic_machine_image_quota_used gauge
+lybic_machine_image_quota_used 3
+# HELP lybic_sandboxes Number of sandboxes.
+# TYPE lybic_sandboxes gauge
+lybic_sandboxes{project="p1",shape="small",status="RUNNING"} 2
+lybic_sandboxes{project="p2",shape="large",status="STOPPED"} 1
+# HELP lybic_up Whether the last refresh succeeded.
+# TYPE lybic_up gauge
+lybic_up 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"lybic_http_port_mappings", "lybic_machine_image_quota_limit", "lybic_machine_image_quota_used",
+		"lybic_sandboxes", "lybic_up", "lybic_sandbox_expiry_minutes"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectorSandboxExpiry(t *testing.T) {
+	server := newStandIn(t, http.StatusOK)
+
+	c := newTestCollector(t, server.URL, nil)
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if n := testutil.CollectAndCount(c, "lybic_sandbox_expiry_minutes"); n != 0 {
+		t.Errorf("expiry series without SandboxExpiry = %d, want 0", n)
+	}
+
+	c = newTestCollector(t, server.URL, &Options{SandboxExpiry: true})
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if n := testutil.CollectAndCount(c, "lybic_sandbox_expiry_minutes"); n != 2 {
+		t.Errorf("expiry series with SandboxExpiry = %d, want 2", n)
+	}
+}
+
+func TestCollectorRefreshFailureKeepsSnapshot(t *testing.T) {
+	good := newStandIn(t, http.StatusOK)
+	c := newTestCollector(t, good.URL, nil)
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	bad := newStandIn(t, http.StatusInternalServerError)
+	c.client = newTestCollector(t, bad.URL, nil).client
+	if err := c.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() error = nil, want the machine image error")
+	}
+
+	expected := `
+# HELP lybic_up Whether the last refresh succeeded.
+# TYPE lybic_up gauge
+lybic_up 0
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "lybic_up"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c, "lybic_sandboxes"); n != 2 {
+		t.Errorf("sandbox series after a failed refresh = %d, want the previous 2", n)
+	}
+}
@@ -0,0 +1,9 @@
+// Package collector exports Lybic organization resources as Prometheus metrics.
+//
+//	The Collector refreshes a snapshot through the lybic.Client at a fixed interval and
+//	serves it on every scrape, so scrapes never hit the Lybic API directly.
+//
+//	c := collector.New(client, &collector.Options{Interval: time.Minute})
+//	go c.Run(ctx)
+//	prometheus.MustRegister(c)
+package collector
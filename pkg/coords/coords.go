@@ -0,0 +1,188 @@
+package coords
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lybic/lybic-sdk-go"
+)
+
+// ErrUnknownScreenSize is returned when a conversion needs a screen size that is not known.
+var ErrUnknownScreenSize = errors.New("screen size is unknown")
+
+// Kind is the kind of a coordinate space.
+type Kind int
+
+const (
+	// KindPixels is absolute screen pixels
+	KindPixels Kind = iota
+	// KindNorm1000 is a 0-1000 grid over the screen
+	KindNorm1000
+	// KindFraction is 0-1 fractions of the screen
+	KindFraction
+	// KindResized is the pixels of a screenshot resized to Space.Width x Space.Height
+	KindResized
+)
+
+// fractionDenominator is the denominator of the FractionalLength written for KindFraction.
+const fractionDenominator = 10000
+
+// Space is a coordinate space.
+type Space struct {
+	Kind Kind
+	// Width and Height are the size of the resized screenshot, only used by KindResized
+	Width  float64
+	Height float64
+}
+
+var (
+	// ScreenPixels is the space of absolute screen pixels
+	ScreenPixels = Space{Kind: KindPixels}
+	// Norm1000 is the space of a 0-1000 grid over the screen
+	Norm1000 = Space{Kind: KindNorm1000}
+	// Fraction is the space of 0-1 fractions of the screen
+	Fraction = Space{Kind: KindFraction}
+)
+
+// Resized returns the space of the pixels of a screenshot downsized to width x height.
+func Resized(width, height float64) Space {
+	return Space{Kind: KindResized, Width: width, Height: height}
+}
+
+func (s Space) String() string {
+	switch s.Kind {
+	case KindPixels:
+		return "pixels"
+	case KindNorm1000:
+		return "norm1000"
+	case KindFraction:
+		return "fraction"
+	case KindResized:
+		return fmt.Sprintf("resized(%gx%g)", s.Width, s.Height)
+	default:
+		return fmt.Sprintf("Kind(%d)", int(s.Kind))
+	}
+}
+
+// Screen is the size of the sandbox screen in pixels.
+type Screen struct {
+	Width  float64
+	Height float64
+}
+
+// ScreenOf returns the screen size reported with a cursor position.
+func ScreenOf(pos lybic.SandboxActionResponseDtoCursorPosition) Screen {
+	return Screen{Width: float64(pos.ScreenWidth), Height: float64(pos.ScreenHeight)}
+}
+
+// Valid reports whether the screen size is known.
+func (s Screen) Valid() bool {
+	return s.Width > 0 && s.Height > 0
+}
+
+// Point is a point in some coordinate space.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Clamp moves a point in screen pixels inside the screen.
+func (s Screen) Clamp(p Point) Point {
+	return Point{
+		X: math.Max(0, math.Min(p.X, s.Width-1)),
+		Y: math.Max(0, math.Min(p.Y, s.Height-1)),
+	}
+}
+
+// scale returns the factors that turn a value of the space into screen pixels.
+func (s Space) scale(screen Screen) (float64, float64, error) {
+	if s.Kind == KindPixels {
+		return 1, 1, nil
+	}
+	if !screen.Valid() {
+		return 0, 0, ErrUnknownScreenSize
+	}
+	switch s.Kind {
+	case KindNorm1000:
+		return screen.Width / 1000, screen.Height / 1000, nil
+	case KindFraction:
+		return screen.Width, screen.Height, nil
+	case KindResized:
+		if s.Width <= 0 || s.Height <= 0 {
+			return 0, 0, fmt.Errorf("resized space has no size: %s", s)
+		}
+		return screen.Width / s.Width, screen.Height / s.Height, nil
+	default:
+		return 0, 0, fmt.Errorf("unknown coordinate space: %s", s)
+	}
+}
+
+// ToScreen converts a point of the space to screen pixels, without clamping.
+func (s Space) ToScreen(p Point, screen Screen) (Point, error) {
+	sx, sy, err := s.scale(screen)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{X: p.X * sx, Y: p.Y * sy}, nil
+}
+
+// FromScreen converts a point in screen pixels to the space, without clamping.
+func (s Space) FromScreen(p Point, screen Screen) (Point, error) {
+	sx, sy, err := s.scale(screen)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{X: p.X / sx, Y: p.Y / sy}, nil
+}
+
+// Convert converts a point from one space to another, clamped to the screen.
+func Convert(p Point, from, to Space, screen Screen) (Point, error) {
+	p, err := from.ToScreen(p, screen)
+	if err != nil {
+		return Point{}, err
+	}
+	if screen.Valid() {
+		p = screen.Clamp(p)
+	}
+	return to.FromScreen(p, screen)
+}
+
+// Pixels converts a Length along an axis of size pixels to screen pixels.
+//
+//	A PixelLength is taken as screen pixels, a FractionalLength as a fraction of size.
+func Pixels(l lybic.Length, size float64) (float64, error) {
+	return pixels(l, size, 1)
+}
+
+// pixels converts a Length to screen pixels, the value of a PixelLength being multiplied by scale.
+func pixels(l lybic.Length, size, scale float64) (float64, error) {
+	switch v := l.(type) {
+	case *lybic.PixelLength:
+		return float64(v.Value) * scale, nil
+	case *lybic.FractionalLength:
+		if v.Denominator == 0 {
+			return 0, fmt.Errorf("fractional length %d/%d has a zero denominator", v.Numerator, v.Denominator)
+		}
+		if size <= 0 {
+			return 0, ErrUnknownScreenSize
+		}
+		return size * float64(v.Numerator) / float64(v.Denominator), nil
+	case nil:
+		return 0, errors.New("length is missing")
+	default:
+		return 0, fmt.Errorf("unknown length type %T", l)
+	}
+}
+
+// length returns the Length of a value of the space.
+func (s Space) length(v float64) lybic.Length {
+	switch s.Kind {
+	case KindNorm1000:
+		return lybic.NewFractionalLength(int(math.Round(v)), 1000)
+	case KindFraction:
+		return lybic.NewFractionalLength(int(math.Round(v*fractionDenominator)), fractionDenominator)
+	default:
+		return lybic.NewPixelLength(int(math.Round(v)))
+	}
+}
@@ -0,0 +1,11 @@
+// Package coords converts coordinates between the spaces used by models and the sandbox screen.
+//
+//	Models emit points in absolute screen pixels, in a 0-1000 grid (e.g. UI-TARS), as 0-1
+//	fractions, or in the pixels of a downsized screenshot. A Space describes one of them;
+//	points and the Length fields of actions are converted through the screen size reported
+//	in SandboxActionResponseDtoCursorPosition and clamped to the screen.
+//
+//	screen := coords.ScreenOf(resp.CursorPosition)
+//	p, err := coords.Convert(coords.Point{X: 500, Y: 500}, coords.Norm1000, coords.ScreenPixels, screen)
+//	err = coords.Rewrite(action, coords.Resized(1280, 720), coords.ScreenPixels, screen)
+package coords
@@ -0,0 +1,106 @@
+package coords
+
+import (
+	"fmt"
+
+	"github.com/lybic/lybic-sdk-go"
+)
+
+// Rewrite rewrites the Length fields of an action in place, from one space to another.
+//
+//	The value of a PixelLength is read in the from space, a FractionalLength is always a
+//	fraction of the screen. Lengths are written as PixelLength for ScreenPixels and Resized
+//	spaces and as FractionalLength for Norm1000 and Fraction. Absolute points are clamped to
+//	the screen when its size is known, relative offsets and swipe distances are only scaled.
+//	Actions without coordinates are left untouched.
+func Rewrite(action lybic.SandboxUseActionDtoActionOneOf, from, to Space, screen Screen) error {
+	r := rewriter{to: to, screen: screen}
+	var err error
+	if r.fromX, r.fromY, err = from.scale(screen); err != nil {
+		return err
+	}
+	if r.toX, r.toY, err = to.scale(screen); err != nil {
+		return err
+	}
+
+	switch v := action.(type) {
+	case *lybic.MouseClickAction:
+		return r.point(&v.X, &v.Y, v.Relative)
+	case *lybic.MouseDoubleClickAction:
+		return r.point(&v.X, &v.Y, v.Relative)
+	case *lybic.MouseTripleClickAction:
+		return r.point(&v.X, &v.Y, v.Relative)
+	case *lybic.MouseMoveAction:
+		return r.point(&v.X, &v.Y, v.Relative)
+	case *lybic.MouseScrollAction:
+		return r.point(&v.X, &v.Y, v.Relative)
+	case *lybic.MouseDragAction:
+		if err := r.point(&v.StartX, &v.StartY, v.StartRelative); err != nil {
+			return err
+		}
+		return r.point(&v.EndX, &v.EndY, v.EndRelative)
+	case *lybic.TouchTapAction:
+		return r.point(&v.X, &v.Y, false)
+	case *lybic.TouchLongPressAction:
+		return r.point(&v.X, &v.Y, false)
+	case *lybic.TouchDragAction:
+		if err := r.point(&v.StartX, &v.StartY, false); err != nil {
+			return err
+		}
+		return r.point(&v.EndX, &v.EndY, false)
+	case *lybic.TouchSwipeAction:
+		if err := r.point(&v.X, &v.Y, false); err != nil {
+			return err
+		}
+		return r.distance(&v.Distance, v.Direction)
+	case *lybic.MobileTapAction:
+		return r.point(&v.X, &v.Y, false)
+	case *lybic.MobileDoubleTapAction:
+		return r.point(&v.X, &v.Y, false)
+	case *lybic.MobileSwipeAction:
+		if err := r.point(&v.StartX, &v.StartY, false); err != nil {
+			return err
+		}
+		return r.point(&v.EndX, &v.EndY, false)
+	}
+	return nil
+}
+
+type rewriter struct {
+	to     Space
+	screen Screen
+	// fromX, fromY, toX and toY turn values of the spaces into screen pixels
+	fromX, fromY float64
+	toX, toY     float64
+}
+
+func (r *rewriter) point(x, y *lybic.Length, relative bool) error {
+	px, err := pixels(*x, r.screen.Width, r.fromX)
+	if err != nil {
+		return fmt.Errorf("x: %w", err)
+	}
+	py, err := pixels(*y, r.screen.Height, r.fromY)
+	if err != nil {
+		return fmt.Errorf("y: %w", err)
+	}
+	p := Point{X: px, Y: py}
+	if !relative && r.screen.Valid() {
+		p = r.screen.Clamp(p)
+	}
+	*x = r.to.length(p.X / r.toX)
+	*y = r.to.length(p.Y / r.toY)
+	return nil
+}
+
+func (r *rewriter) distance(l *lybic.Length, direction string) error {
+	size, from, to := r.screen.Height, r.fromY, r.toY
+	if direction == "left" || direction == "right" {
+		size, from, to = r.screen.Width, r.fromX, r.toX
+	}
+	d, err := pixels(*l, size, from)
+	if err != nil {
+		return fmt.Errorf("distance: %w", err)
+	}
+	*l = r.to.length(d / to)
+	return nil
+}
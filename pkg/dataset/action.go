@@ -0,0 +1,217 @@
+package dataset
+
+import (
+	"fmt"
+
+	"github.com/lybic/lybic-sdk-go"
+	"github.com/lybic/lybic-sdk-go/pkg/coords"
+)
+
+// action is a format independent action with coordinates in pixels.
+type action struct {
+	// kind is one of click, right_click, middle_click, double_click, triple_click, move, scroll,
+	// drag, long_press, swipe, type, hotkey, key_down, key_up, home, back, screenshot, wait,
+	// finished and failed
+	kind       string
+	x, y       float64
+	endX, endY float64
+	// text is the typed content, the keys or the final message
+	text string
+	// direction is the direction the finger moves in a swipe
+	direction string
+	// stepVertical and stepHorizontal are the scroll steps, positive steps scroll down and right
+	stepVertical   int
+	stepHorizontal int
+	// duration is in milliseconds
+	duration int
+}
+
+// hasPoint reports whether the action targets a point of the screen.
+func (a *action) hasPoint() bool {
+	switch a.kind {
+	case "click", "right_click", "middle_click", "double_click", "triple_click", "move", "scroll", "drag", "long_press", "swipe":
+		return true
+	}
+	return false
+}
+
+// hasEnd reports whether the action ends at another point.
+func (a *action) hasEnd() bool {
+	return a.kind == "drag" || a.kind == "swipe"
+}
+
+// point converts a pair of Lengths to pixels, relative points are offset by the cursor.
+func (s *Step) point(x, y lybic.Length, relative bool) (float64, float64, error) {
+	px, err := coords.Pixels(x, s.ScreenWidth)
+	if err != nil {
+		return 0, 0, err
+	}
+	py, err := coords.Pixels(y, s.ScreenHeight)
+	if err != nil {
+		return 0, 0, err
+	}
+	if relative {
+		px, py = px+s.CursorX, py+s.CursorY
+	}
+	return px, py, nil
+}
+
+// convert turns the action of a step into its format independent form.
+func convert(s *Step) (action, error) {
+	var a action
+	var err error
+	switch v := s.Action.(type) {
+	case *lybic.MouseClickAction:
+		a.kind = clickKind(v.Button)
+		a.x, a.y, err = s.point(v.X, v.Y, v.Relative)
+	case *lybic.MouseDoubleClickAction:
+		a.kind = "double_click"
+		a.x, a.y, err = s.point(v.X, v.Y, v.Relative)
+	case *lybic.MouseTripleClickAction:
+		a.kind = "triple_click"
+		a.x, a.y, err = s.point(v.X, v.Y, v.Relative)
+	case *lybic.MouseMoveAction:
+		a.kind = "move"
+		a.x, a.y, err = s.point(v.X, v.Y, v.Relative)
+	case *lybic.MouseScrollAction:
+		a.kind = "scroll"
+		a.stepVertical, a.stepHorizontal = v.StepVertical, v.StepHorizontal
+		a.x, a.y, err = s.point(v.X, v.Y, v.Relative)
+	case *lybic.MouseDragAction:
+		a.kind = "drag"
+		if a.x, a.y, err = s.point(v.StartX, v.StartY, v.StartRelative); err == nil {
+			a.endX, a.endY, err = s.point(v.EndX, v.EndY, false)
+			if v.EndRelative {
+				a.endX, a.endY = a.endX+a.x, a.endY+a.y
+			}
+		}
+	case *lybic.KeyboardTypeAction:
+		a.kind, a.text = "type", v.Content
+	case *lybic.KeyboardHotkeyAction:
+		a.kind, a.text = "hotkey", v.Keys
+	case *lybic.KeyDownAction:
+		a.kind, a.text = "key_down", v.Key
+	case *lybic.KeyUpAction:
+		a.kind, a.text = "key_up", v.Key
+	case *lybic.ScreenshotAction, *lybic.MobileScreenshotAction:
+		a.kind = "screenshot"
+	case *lybic.WaitAction:
+		a.kind, a.duration = "wait", v.Duration
+	case *lybic.MobileWaitAction:
+		a.kind, a.duration = "wait", v.Duration
+	case *lybic.FinishedAction:
+		a.kind, a.text = "finished", deref(v.Message)
+	case *lybic.MobileFinishedAction:
+		a.kind, a.text = "finished", deref(v.Message)
+	case *lybic.FailedAction:
+		a.kind, a.text = "failed", deref(v.Message)
+	case *lybic.MobileFailedAction:
+		a.kind, a.text = "failed", deref(v.Message)
+	case *lybic.TouchTapAction:
+		a.kind = "click"
+		a.x, a.y, err = s.point(v.X, v.Y, false)
+	case *lybic.MobileTapAction:
+		a.kind = "click"
+		a.x, a.y, err = s.point(v.X, v.Y, false)
+	case *lybic.MobileDoubleTapAction:
+		a.kind = "double_click"
+		a.x, a.y, err = s.point(v.X, v.Y, false)
+	case *lybic.TouchLongPressAction:
+		a.kind, a.duration = "long_press", v.Duration
+		a.x, a.y, err = s.point(v.X, v.Y, false)
+	case *lybic.TouchDragAction:
+		a.kind = "drag"
+		if a.x, a.y, err = s.point(v.StartX, v.StartY, false); err == nil {
+			a.endX, a.endY, err = s.point(v.EndX, v.EndY, false)
+		}
+	case *lybic.TouchSwipeAction:
+		a.kind, a.direction = "swipe", v.Direction
+		if a.x, a.y, err = s.point(v.X, v.Y, false); err == nil {
+			err = a.swipeEnd(s, v.Distance)
+		}
+	case *lybic.MobileSwipeAction:
+		a.kind, a.duration = "swipe", v.Duration
+		if a.x, a.y, err = s.point(v.StartX, v.StartY, false); err == nil {
+			a.endX, a.endY, err = s.point(v.EndX, v.EndY, false)
+			a.direction = direction(a.endX-a.x, a.endY-a.y)
+		}
+	case *lybic.MobileTypeAction:
+		a.kind, a.text = "type", v.Content
+	case *lybic.MobileHotkeyAction:
+		a.kind, a.text = "hotkey", v.Key
+	case *lybic.AndroidHomeAction, *lybic.MobileHomeAction:
+		a.kind = "home"
+	case *lybic.AndroidBackAction, *lybic.MobileBackAction:
+		a.kind = "back"
+	default:
+		return a, fmt.Errorf("%w: %T", ErrUnsupportedAction, s.Action)
+	}
+	return a, err
+}
+
+// swipeEnd computes the end of a swipe from its direction and distance.
+func (a *action) swipeEnd(s *Step, distance lybic.Length) error {
+	a.endX, a.endY = a.x, a.y
+	switch a.direction {
+	case "up", "down":
+		d, err := coords.Pixels(distance, s.ScreenHeight)
+		if err != nil {
+			return err
+		}
+		if a.direction == "up" {
+			d = -d
+		}
+		a.endY += d
+	case "left", "right":
+		d, err := coords.Pixels(distance, s.ScreenWidth)
+		if err != nil {
+			return err
+		}
+		if a.direction == "left" {
+			d = -d
+		}
+		a.endX += d
+	default:
+		return fmt.Errorf("unknown swipe direction %q", a.direction)
+	}
+	return nil
+}
+
+func clickKind(button int) string {
+	switch button {
+	case 2:
+		return "right_click"
+	case 4:
+		return "middle_click"
+	default:
+		return "click"
+	}
+}
+
+// direction returns the main direction of a move by (dx, dy).
+func direction(dx, dy float64) string {
+	if abs(dx) > abs(dy) {
+		if dx < 0 {
+			return "left"
+		}
+		return "right"
+	}
+	if dy < 0 {
+		return "up"
+	}
+	return "down"
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
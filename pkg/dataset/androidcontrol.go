@@ -0,0 +1,132 @@
+package dataset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+// AndroidControlAction is an action of an AndroidControl episode.
+//
+//	X and Y are in pixels as in AndroidControl, TouchYX and LiftYX are the (y, x) points
+//	normalized to [0, 1] as in AITW.
+type AndroidControlAction struct {
+	ActionType string      `json:"action_type"`
+	X          *int        `json:"x,omitempty"`
+	Y          *int        `json:"y,omitempty"`
+	Direction  string      `json:"direction,omitempty"`
+	Text       string      `json:"text,omitempty"`
+	GoalStatus string      `json:"goal_status,omitempty"`
+	TouchYX    *[2]float64 `json:"touch_yx,omitempty"`
+	LiftYX     *[2]float64 `json:"lift_yx,omitempty"`
+}
+
+// AndroidControlEpisode is an episode in the AndroidControl layout.
+type AndroidControlEpisode struct {
+	EpisodeId         string                 `json:"episode_id"`
+	Goal              string                 `json:"goal"`
+	ScreenShots       []string               `json:"screenshots"`
+	ScreenShotWidths  []int                  `json:"screenshot_widths"`
+	ScreenShotHeights []int                  `json:"screenshot_heights"`
+	Actions           []AndroidControlAction `json:"actions"`
+	StepInstructions  []string               `json:"step_instructions"`
+}
+
+// WriteAndroidControl writes an episode to dir as <id>.json in the AndroidControl layout,
+// with the screenshots of the steps next to it.
+//
+//	Swipes and drags become scroll actions, whose direction is the one the content moves in,
+//	the opposite of the finger. Keyboard shortcuts, double clicks and desktop mouse buttons
+//	other than the left one are not supported.
+func WriteAndroidControl(dir string, episode *Episode) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	out := AndroidControlEpisode{EpisodeId: episode.Id, Goal: episode.Instruction}
+	for i := range episode.Steps {
+		step := &episode.Steps[i]
+		a, err := convert(step)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+		if a.kind == "screenshot" {
+			continue
+		}
+		action, err := androidControlAction(step, &a)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		screenShot, err := writeScreenShot(dir, fmt.Sprintf("%s_%d", episode.Id, len(out.Actions)), step)
+		if err != nil {
+			return err
+		}
+		out.ScreenShots = append(out.ScreenShots, screenShot)
+		out.ScreenShotWidths = append(out.ScreenShotWidths, int(step.ScreenWidth))
+		out.ScreenShotHeights = append(out.ScreenShotHeights, int(step.ScreenHeight))
+		out.Actions = append(out.Actions, action)
+		out.StepInstructions = append(out.StepInstructions, step.Thoughts)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, episode.Id+".json"), append(data, '\n'), 0o644)
+}
+
+func androidControlAction(step *Step, a *action) (AndroidControlAction, error) {
+	var out AndroidControlAction
+	switch a.kind {
+	case "click":
+		out.ActionType = "click"
+	case "long_press":
+		out.ActionType = "long_press"
+	case "drag", "swipe":
+		out.ActionType = "scroll"
+		out.Direction = opposite(direction(a.endX-a.x, a.endY-a.y))
+	case "type":
+		out.ActionType, out.Text = "input_text", a.text
+	case "home":
+		out.ActionType = "navigate_home"
+	case "back":
+		out.ActionType = "navigate_back"
+	case "wait":
+		out.ActionType = "wait"
+	case "finished":
+		out.ActionType, out.GoalStatus = "status", "successful"
+	case "failed":
+		out.ActionType, out.GoalStatus = "status", "infeasible"
+	default:
+		return out, fmt.Errorf("%w: %s in AndroidControl", ErrUnsupportedAction, a.kind)
+	}
+
+	if a.hasPoint() {
+		x, y := int(a.x), int(a.y)
+		out.X, out.Y = &x, &y
+		if step.ScreenWidth > 0 && step.ScreenHeight > 0 {
+			touch := [2]float64{a.y / step.ScreenHeight, a.x / step.ScreenWidth}
+			lift := touch
+			if a.hasEnd() {
+				lift = [2]float64{a.endY / step.ScreenHeight, a.endX / step.ScreenWidth}
+			}
+			out.TouchYX, out.LiftYX = &touch, &lift
+		}
+	}
+	return out, nil
+}
+
+func opposite(direction string) string {
+	switch direction {
+	case "up":
+		return "down"
+	case "down":
+		return "up"
+	case "left":
+		return "right"
+	default:
+		return "left"
+	}
+}
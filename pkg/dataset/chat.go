@@ -0,0 +1,118 @@
+package dataset
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+// ChatMessage is a message of a ChatSample.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatSample is a line of a chat-format SFT file. The user message holds an <image>
+// placeholder for every entry of Images.
+type ChatSample struct {
+	Messages []ChatMessage `json:"messages"`
+	Images   []string      `json:"images"`
+}
+
+// ChatOptions configures WriteChatSFT.
+type ChatOptions struct {
+	// System is the system prompt, no system message is written if empty
+	System string
+
+	// ImageDir is where the screenshots are written, defaults to the current directory
+	ImageDir string
+
+	// ImagePrefix is prepended to the image file names in the samples, e.g. a path
+	// relative to the dataset file
+	ImagePrefix string
+}
+
+// WriteChatSFT appends one chat sample per step to w. The user turn is the screenshot and
+// the instruction, the assistant turn is the thoughts and the action as a function call,
+// e.g. "Thought: ...\nAction: click(x=512, y=300)", coordinates in pixels. opts can be nil.
+func WriteChatSFT(w io.Writer, episode *Episode, opts *ChatOptions) error {
+	var o ChatOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.ImageDir != "" {
+		if err := os.MkdirAll(o.ImageDir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for i := range episode.Steps {
+		step := &episode.Steps[i]
+		a, err := convert(step)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+		if a.kind == "screenshot" {
+			continue
+		}
+
+		sample := ChatSample{Images: []string{}}
+		if o.System != "" {
+			sample.Messages = append(sample.Messages, ChatMessage{Role: "system", Content: o.System})
+		}
+		prompt := episode.Instruction
+		screenShot, err := writeScreenShot(o.ImageDir, fmt.Sprintf("%s_%d", episode.Id, i), step)
+		if err != nil {
+			return err
+		}
+		if screenShot != "" {
+			prompt = "<image>" + prompt
+			sample.Images = append(sample.Images, o.ImagePrefix+filepath.ToSlash(screenShot))
+		}
+		var answer strings.Builder
+		if step.Thoughts != "" {
+			fmt.Fprintf(&answer, "Thought: %s\n", step.Thoughts)
+		}
+		fmt.Fprintf(&answer, "Action: %s", call(&a))
+		sample.Messages = append(sample.Messages,
+			ChatMessage{Role: "user", Content: prompt},
+			ChatMessage{Role: "assistant", Content: answer.String()})
+
+		if err := enc.Encode(sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// call renders an action as a function call.
+func call(a *action) string {
+	args := make([]string, 0, 4)
+	if a.hasPoint() {
+		args = append(args, fmt.Sprintf("x=%d", int(a.x)), fmt.Sprintf("y=%d", int(a.y)))
+	}
+	if a.hasEnd() {
+		args = append(args, fmt.Sprintf("end_x=%d", int(a.endX)), fmt.Sprintf("end_y=%d", int(a.endY)))
+	}
+	switch a.kind {
+	case "scroll":
+		args = append(args, fmt.Sprintf("dy=%d", a.stepVertical), fmt.Sprintf("dx=%d", a.stepHorizontal))
+	case "type":
+		args = append(args, fmt.Sprintf("content=%q", a.text))
+	case "hotkey", "key_down", "key_up":
+		args = append(args, fmt.Sprintf("key=%q", a.text))
+	case "finished", "failed":
+		if a.text != "" {
+			args = append(args, fmt.Sprintf("message=%q", a.text))
+		}
+	case "wait", "long_press":
+		args = append(args, fmt.Sprintf("duration=%d", a.duration))
+	}
+	return a.kind + "(" + strings.Join(args, ", ") + ")"
+}
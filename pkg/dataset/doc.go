@@ -0,0 +1,11 @@
+// Package dataset exports recorded Lybic runs to common GUI-agent dataset formats.
+//
+//	An Episode is a task instruction and the steps taken to solve it, each step being the
+//	screenshot seen before an action, the action and the thoughts of the model. Episodes are
+//	usually built from a trajectory written by lybic.NewTrajectoryRecorder. Coordinates given
+//	as FractionalLength are converted to pixels with the screen size recorded for the step.
+//
+//	entries, _ := lybic.ReadTrajectory(file)
+//	episode, _ := dataset.FromTrajectory("task-1", "Open the settings", entries)
+//	err := dataset.WriteOSWorld("out/task-1", episode)
+package dataset
@@ -0,0 +1,111 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go"
+	"github.com/lybic/lybic-sdk-go/pkg/coords"
+)
+
+// ErrUnsupportedAction is returned when an action has no equivalent in the target format.
+var ErrUnsupportedAction = errors.New("action is not supported by the format")
+
+// ErrUnknownScreenSize is returned when a FractionalLength can not be converted to pixels.
+var ErrUnknownScreenSize = coords.ErrUnknownScreenSize
+
+// Step is one action of an episode.
+type Step struct {
+	// ScreenShot is the image of the screen before the action, can be empty
+	ScreenShot []byte
+	// ScreenWidth and ScreenHeight are the size of the screen, in pixels
+	ScreenWidth  float64
+	ScreenHeight float64
+	// CursorX and CursorY are the cursor position before the action, used for relative moves
+	CursorX float64
+	CursorY float64
+
+	Action   lybic.SandboxUseActionDtoActionOneOf
+	Thoughts string
+	Time     time.Time
+}
+
+// Episode is a task instruction and the steps taken to solve it.
+type Episode struct {
+	Id          string
+	Instruction string
+	Steps       []Step
+}
+
+// FromTrajectory builds an episode from the entries of a recorded trajectory.
+//
+//	The observation of an action is the screenshot, screen size and cursor position of the
+//	entry that precedes it, usually a PreviewSandbox call or the previous action. Actions
+//	that failed are left out.
+func FromTrajectory(id, instruction string, entries []lybic.TrajectoryEntry) (*Episode, error) {
+	episode := &Episode{Id: id, Instruction: instruction}
+	var screenShot []byte
+	var position *lybic.SandboxActionResponseDtoCursorPosition
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Error != "" {
+			continue
+		}
+		action, err := entry.DecodeAction()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode action of entry %d: %w", entry.Seq, err)
+		}
+
+		if action != nil {
+			step := Step{ScreenShot: screenShot, Action: action, Time: entry.StartedAt}
+			if position == nil {
+				// the first action has no observation, its own response still tells the screen size
+				position = entry.CursorPosition
+			}
+			if position != nil {
+				step.ScreenWidth, step.ScreenHeight = float64(position.ScreenWidth), float64(position.ScreenHeight)
+				step.CursorX, step.CursorY = float64(position.X), float64(position.Y)
+			}
+			if entry.Thoughts != nil {
+				step.Thoughts = *entry.Thoughts
+			}
+			episode.Steps = append(episode.Steps, step)
+		}
+
+		if len(entry.ScreenShot) > 0 {
+			screenShot = entry.ScreenShot
+		}
+		if entry.CursorPosition != nil {
+			position = entry.CursorPosition
+		}
+	}
+	return episode, nil
+}
+
+// writeScreenShot writes the screenshot of a step to dir and returns the file name,
+// or an empty name if the step has no screenshot.
+func writeScreenShot(dir, name string, step *Step) (string, error) {
+	if len(step.ScreenShot) == 0 {
+		return "", nil
+	}
+	name += imageExtension(step.ScreenShot)
+	if err := os.WriteFile(filepath.Join(dir, name), step.ScreenShot, 0o644); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func imageExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ".png"
+	}
+}
@@ -0,0 +1,186 @@
+package dataset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+// OSWorldStep is a line of an OSWorld traj.jsonl file.
+type OSWorldStep struct {
+	StepNum         int            `json:"step_num"`
+	ActionTimestamp string         `json:"action_timestamp"`
+	Action          string         `json:"action"`
+	Response        string         `json:"response,omitempty"`
+	Reward          float64        `json:"reward"`
+	Done            bool           `json:"done"`
+	Info            map[string]any `json:"info"`
+	ScreenShotFile  string         `json:"screenshot_file,omitempty"`
+}
+
+// OSWorldTask is the task.json written next to traj.jsonl.
+type OSWorldTask struct {
+	Id          string `json:"id"`
+	Instruction string `json:"instruction"`
+}
+
+// WriteOSWorld writes an episode to dir in the layout of the OSWorld results: a task.json,
+// a traj.jsonl with one pyautogui action per line, and the screenshots of the steps.
+//
+//	Waits, successes and failures are written as the WAIT, DONE and FAIL special actions,
+//	screenshot actions are left out. Android navigation actions are not supported.
+//	pyautogui can only type ASCII, text with other characters is pasted through the
+//	clipboard with pyperclip and ctrl+v, which needs pyperclip on the replaying machine and
+//	an application that pastes on ctrl+v.
+func WriteOSWorld(dir string, episode *Episode) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	task, err := json.MarshalIndent(OSWorldTask{Id: episode.Id, Instruction: episode.Instruction}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "task.json"), append(task, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(dir, "traj.jsonl"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	enc := json.NewEncoder(file)
+	enc.SetEscapeHTML(false)
+
+	n := 0
+	for i := range episode.Steps {
+		step := &episode.Steps[i]
+		a, err := convert(step)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+		if a.kind == "screenshot" {
+			continue
+		}
+		code, err := pyautogui(&a)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		n++
+		timestamp := step.Time.Format("20060102@150405")
+		screenShot, err := writeScreenShot(dir, fmt.Sprintf("step_%d_%s", n, timestamp), step)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(OSWorldStep{
+			StepNum:         n,
+			ActionTimestamp: timestamp,
+			Action:          code,
+			Response:        step.Thoughts,
+			Done:            a.kind == "finished" || a.kind == "failed",
+			Info:            map[string]any{},
+			ScreenShotFile:  screenShot,
+		}); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// pyautogui renders an action as pyautogui code.
+func pyautogui(a *action) (string, error) {
+	x, y := int(a.x), int(a.y)
+	switch a.kind {
+	case "click":
+		return fmt.Sprintf("pyautogui.click(%d, %d)", x, y), nil
+	case "right_click":
+		return fmt.Sprintf("pyautogui.rightClick(%d, %d)", x, y), nil
+	case "middle_click":
+		return fmt.Sprintf("pyautogui.middleClick(%d, %d)", x, y), nil
+	case "double_click":
+		return fmt.Sprintf("pyautogui.doubleClick(%d, %d)", x, y), nil
+	case "triple_click":
+		return fmt.Sprintf("pyautogui.tripleClick(%d, %d)", x, y), nil
+	case "move":
+		return fmt.Sprintf("pyautogui.moveTo(%d, %d)", x, y), nil
+	case "scroll":
+		var calls []string
+		if a.stepVertical != 0 {
+			// pyautogui scrolls up on positive clicks
+			calls = append(calls, fmt.Sprintf("pyautogui.scroll(%d, x=%d, y=%d)", -a.stepVertical, x, y))
+		}
+		if a.stepHorizontal != 0 {
+			calls = append(calls, fmt.Sprintf("pyautogui.hscroll(%d, x=%d, y=%d)", a.stepHorizontal, x, y))
+		}
+		return strings.Join(calls, "; "), nil
+	case "drag", "swipe":
+		duration := max(float64(a.duration)/1000, 0.5)
+		return fmt.Sprintf("pyautogui.moveTo(%d, %d); pyautogui.dragTo(%d, %d, duration=%g, button='left')",
+			x, y, int(a.endX), int(a.endY), duration), nil
+	case "long_press":
+		return fmt.Sprintf("import time; pyautogui.mouseDown(%d, %d); time.sleep(%g); pyautogui.mouseUp(%d, %d)",
+			x, y, float64(a.duration)/1000, x, y), nil
+	case "type":
+		if !isASCII(a.text) {
+			return fmt.Sprintf("import pyperclip; pyperclip.copy(%s); pyautogui.hotkey('ctrl', 'v')", strconv.Quote(a.text)), nil
+		}
+		return fmt.Sprintf("pyautogui.typewrite(%s)", strconv.Quote(a.text)), nil
+	case "hotkey":
+		var calls []string
+		for _, chord := range strings.Fields(a.text) {
+			keys := strings.Split(chord, "+")
+			for i, key := range keys {
+				keys[i] = strconv.Quote(pyautoguiKey(key))
+			}
+			calls = append(calls, fmt.Sprintf("pyautogui.hotkey(%s)", strings.Join(keys, ", ")))
+		}
+		return strings.Join(calls, "; "), nil
+	case "key_down":
+		return fmt.Sprintf("pyautogui.keyDown(%s)", strconv.Quote(pyautoguiKey(a.text))), nil
+	case "key_up":
+		return fmt.Sprintf("pyautogui.keyUp(%s)", strconv.Quote(pyautoguiKey(a.text))), nil
+	case "wait":
+		return "WAIT", nil
+	case "finished":
+		return "DONE", nil
+	case "failed":
+		return "FAIL", nil
+	default:
+		return "", fmt.Errorf("%w: %s in OSWorld", ErrUnsupportedAction, a.kind)
+	}
+}
+
+// pyautoguiKeys maps the xdotool key names that differ in pyautogui.
+var pyautoguiKeys = map[string]string{
+	"return":    "enter",
+	"escape":    "esc",
+	"page_up":   "pageup",
+	"page_down": "pagedown",
+	"super":     "win",
+	"control":   "ctrl",
+	"prior":     "pageup",
+	"next":      "pagedown",
+}
+
+func pyautoguiKey(key string) string {
+	key = strings.ToLower(key)
+	if k, ok := pyautoguiKeys[key]; ok {
+		return k
+	}
+	return key
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
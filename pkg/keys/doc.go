@@ -0,0 +1,10 @@
+// Package keys normalizes key names emitted by models into actions the sandbox understands.
+//
+//	KeyboardHotkeyAction.Keys and HoldKey use xdotool syntax ("ctrl+shift+t", "Return"),
+//	while models write "Cmd+C", "Control+Shift+Esc", "ArrowDown", "KEYCODE_BACK" or "VK_RETURN".
+//	Parse reads all of these dialects into chords of modifiers and a key, which render back
+//	to xdotool syntax or to Android key names. Cmd is mapped to Ctrl on Linux and Windows.
+//
+//	keys, err := keys.Normalize("Cmd+Shift+ArrowDown", keys.OS(shape.Os))
+//	// keys == "ctrl+shift+Down"
+package keys
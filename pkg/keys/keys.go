@@ -0,0 +1,312 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lybic/lybic-sdk-go"
+)
+
+// ErrUnknownKey is wrapped by UnknownKeyError.
+var ErrUnknownKey = errors.New("unknown key")
+
+// ErrNotRepresentable is returned when a chord has no equivalent on the target.
+var ErrNotRepresentable = errors.New("key can not be represented on the target")
+
+// UnknownKeyError lists the keys that could not be parsed.
+type UnknownKeyError struct {
+	Keys []string
+}
+
+func (e *UnknownKeyError) Error() string {
+	return fmt.Sprintf("unknown keys: %s", strings.Join(e.Keys, ", "))
+}
+
+func (e *UnknownKeyError) Unwrap() error {
+	return ErrUnknownKey
+}
+
+// OS is the operating system keys are rendered for, the values match the shape os.
+type OS string
+
+const (
+	Linux   OS = "Linux"
+	Windows OS = "Windows"
+	Android OS = "Android"
+	MacOS   OS = "macOS"
+)
+
+func (os OS) is(other OS) bool {
+	return strings.EqualFold(string(os), string(other))
+}
+
+// Modifier is a set of modifier keys.
+type Modifier uint8
+
+const (
+	Ctrl Modifier = 1 << iota
+	Shift
+	Alt
+	// Super is the Windows or Super key, kept as is on every desktop
+	Super
+	// Cmd is the macOS Command key, it becomes Ctrl on Linux and Windows
+	Cmd
+)
+
+// modifierOrder is the order modifiers are rendered in, with their xdotool names.
+var modifierOrder = []struct {
+	modifier Modifier
+	name     string
+}{
+	{Ctrl, "ctrl"},
+	{Alt, "alt"},
+	{Shift, "shift"},
+	{Super, "super"},
+	{Cmd, "cmd"},
+}
+
+// Chord is a key pressed together with modifiers.
+type Chord struct {
+	Modifiers Modifier
+	// Key is the canonical name of the key, e.g. "a", "enter" or "pagedown",
+	// empty when only modifiers are pressed
+	Key string
+
+	// keycode is set when the key was written as an Android key name, AndroidKey then
+	// renders it as written
+	keycode bool
+}
+
+// String returns the canonical form of the chord, e.g. "ctrl+shift+t".
+func (c Chord) String() string {
+	parts := c.modifierNames(false)
+	if c.Key != "" {
+		parts = append(parts, c.Key)
+	}
+	return strings.Join(parts, "+")
+}
+
+func (c Chord) modifierNames(cmdAsCtrl bool) []string {
+	modifiers := c.Modifiers
+	if cmdAsCtrl && modifiers&Cmd != 0 {
+		modifiers = modifiers&^Cmd | Ctrl
+	}
+	var names []string
+	for _, m := range modifierOrder {
+		if modifiers&m.modifier != 0 {
+			names = append(names, m.name)
+		}
+	}
+	return names
+}
+
+// Xdotool renders the chord in xdotool key syntax for the target OS.
+//
+//	Cmd becomes ctrl on Linux and Windows and super on macOS.
+func (c Chord) Xdotool(os OS) (string, error) {
+	if os.is(Android) {
+		return "", fmt.Errorf("%w: xdotool syntax is not used on Android", ErrNotRepresentable)
+	}
+	parts := c.modifierNames(!os.is(MacOS))
+	for i, name := range parts {
+		if name == "cmd" {
+			parts[i] = "super"
+		}
+	}
+	if c.Key != "" {
+		k := byName[c.Key]
+		if k == nil || k.xdotool == "" {
+			return "", fmt.Errorf("%w: %s in xdotool", ErrNotRepresentable, c.Key)
+		}
+		parts = append(parts, k.xdotool)
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("%w: empty chord", ErrNotRepresentable)
+	}
+	return strings.Join(parts, "+"), nil
+}
+
+// AndroidKey renders the chord as an Android key name, e.g. "KEYCODE_BACK". Chords with
+// modifiers can not be represented.
+//
+//	On a phone "home" means the home button, so it renders as KEYCODE_HOME rather than
+//	KEYCODE_MOVE_HOME, unless it was parsed from KEYCODE_MOVE_HOME.
+func (c Chord) AndroidKey() (string, error) {
+	if c.Modifiers != 0 {
+		return "", fmt.Errorf("%w: %s has modifiers on Android", ErrNotRepresentable, c)
+	}
+	if c.Key == "home" && !c.keycode {
+		return byName["homescreen"].android, nil
+	}
+	k := byName[c.Key]
+	if k == nil || k.android == "" {
+		return "", fmt.Errorf("%w: %s on Android", ErrNotRepresentable, c)
+	}
+	return k.android, nil
+}
+
+// spacedSeparator matches a "+" or "-" with the spaces around it, as in "Ctrl + C".
+var spacedSeparator = regexp.MustCompile(`\s*([+-])\s*`)
+
+// Parse parses a key sequence in any supported dialect. Chords are separated by spaces and
+// keys in a chord by "+", or by "-" after a modifier as in "Ctrl-C".
+//
+//	Spaces around "+" and "-" are dropped, so "Control + Shift + Esc" is one chord.
+//	Unknown keys are collected and reported together in an *UnknownKeyError.
+func Parse(s string) ([]Chord, error) {
+	fields := strings.Fields(spacedSeparator.ReplaceAllString(strings.TrimSpace(s), "$1"))
+	if len(fields) == 0 {
+		return nil, errors.New("key sequence is empty")
+	}
+	var chords []Chord
+	var unknown []string
+	for _, field := range fields {
+		chord, missing, err := parseChord(field)
+		if err != nil {
+			return nil, err
+		}
+		unknown = append(unknown, missing...)
+		chords = append(chords, chord)
+	}
+	if len(unknown) > 0 {
+		return nil, &UnknownKeyError{Keys: unknown}
+	}
+	return chords, nil
+}
+
+// parseChord parses one chord, it returns the tokens that are not known keys.
+func parseChord(s string) (Chord, []string, error) {
+	var chord Chord
+	var unknown []string
+	for _, token := range splitChord(s) {
+		if token == "" {
+			return chord, nil, fmt.Errorf("chord %q has an empty key", s)
+		}
+		modifier, k, ok := lookup(token)
+		switch {
+		case !ok:
+			unknown = append(unknown, token)
+		case modifier != 0:
+			chord.Modifiers |= modifier
+		case chord.Key != "":
+			return chord, nil, fmt.Errorf("chord %q presses more than one key", s)
+		default:
+			chord.Key = k.name
+			chord.keycode = strings.HasPrefix(strings.ToUpper(token), "KEYCODE_")
+		}
+	}
+	return chord, unknown, nil
+}
+
+// splitChord splits a chord into its tokens.
+func splitChord(s string) []string {
+	switch {
+	case s == "+" || s == "-":
+		return []string{s}
+	case strings.Contains(s, "+"):
+		parts := strings.Split(s, "+")
+		// "ctrl++" presses the + key
+		if strings.HasSuffix(s, "++") {
+			parts = append(parts[:len(parts)-2], "+")
+		}
+		return parts
+	case strings.Contains(s[1:], "-"):
+		// "Ctrl-C": only split on "-" when the parts before the last one are modifiers
+		parts := strings.Split(s, "-")
+		if strings.HasSuffix(s, "--") {
+			parts = append(parts[:len(parts)-2], "-")
+		}
+		for _, part := range parts[:len(parts)-1] {
+			if m, _, _ := lookup(part); m == 0 {
+				return []string{s}
+			}
+		}
+		return parts
+	}
+	return []string{s}
+}
+
+// lookup finds a token in the tables of the dialect it is written in.
+func lookup(token string) (Modifier, *key, bool) {
+	upper := strings.ToUpper(token)
+	switch {
+	case strings.HasPrefix(upper, "KEYCODE_"):
+		if m, ok := androidModifiers[upper]; ok {
+			return m, nil, true
+		}
+		k, ok := byAndroid[upper]
+		return 0, k, ok
+	case strings.HasPrefix(upper, "VK_"):
+		if m, ok := vkModifiers[upper]; ok {
+			return m, nil, true
+		}
+		k, ok := byVK[upper]
+		return 0, k, ok
+	}
+	folded := fold(token)
+	if m, ok := modifierAliases[folded]; ok {
+		return m, nil, true
+	}
+	k, ok := byAlias[folded]
+	return 0, k, ok
+}
+
+// fold lower-cases a key name and drops underscores, so that "Page_Down", "PageDown" and
+// "pagedown" compare equal. Single characters are only lower-cased.
+func fold(name string) string {
+	if utf8.RuneCountInString(name) == 1 {
+		return strings.ToLower(name)
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// Xdotool renders chords in xdotool key syntax for the target OS, separated by spaces.
+func Xdotool(chords []Chord, os OS) (string, error) {
+	parts := make([]string, len(chords))
+	for i, chord := range chords {
+		s, err := chord.Xdotool(os)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// Normalize parses a key sequence and renders it for the target OS: in xdotool key syntax
+// on desktops, or as a single Android key name on Android.
+func Normalize(s string, os OS) (string, error) {
+	chords, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if os.is(Android) {
+		if len(chords) != 1 {
+			return "", fmt.Errorf("%w: %d chords on Android", ErrNotRepresentable, len(chords))
+		}
+		return chords[0].AndroidKey()
+	}
+	return Xdotool(chords, os)
+}
+
+// Action parses a key sequence and returns the action that presses it on the target OS,
+// a MobileHotkeyAction on Android and a KeyboardHotkeyAction elsewhere.
+func Action(s string, os OS) (lybic.SandboxUseActionDtoActionOneOf, error) {
+	keys, err := Normalize(s, os)
+	if err != nil {
+		return nil, err
+	}
+	if os.is(Android) {
+		return lybic.NewMobileHotkeyAction(keys), nil
+	}
+	return lybic.NewKeyboardHotkeyAction(keys), nil
+}
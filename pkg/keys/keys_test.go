@@ -0,0 +1,93 @@
+package keys
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		dialect string
+		in      string
+		os      OS
+		want    string
+	}{
+		{"xdotool", "ctrl+shift+t", Linux, "ctrl+shift+t"},
+		{"xdotool", "Return", Linux, "Return"},
+		{"xdotool", "ctrl+a ctrl+c", Linux, "ctrl+a ctrl+c"},
+		{"xdotool", "Page_Down", Windows, "Page_Down"},
+		{"xdotool", "ctrl++", Linux, "ctrl+plus"},
+
+		{"model", "Cmd+C", Linux, "ctrl+c"},
+		{"model", "Cmd+C", MacOS, "super+c"},
+		{"model", "Control+Shift+Esc", Windows, "ctrl+shift+Escape"},
+		{"model", "Cmd+Shift+ArrowDown", Linux, "ctrl+shift+Down"},
+		{"model", "Ctrl-C", Linux, "ctrl+c"},
+		{"model", "Ctrl + C", Linux, "ctrl+c"},
+		{"model", "Control + Shift + Esc", Windows, "ctrl+shift+Escape"},
+		{"model", "Ctrl - C", Linux, "ctrl+c"},
+		{"model", "ctrl + +", Linux, "ctrl+plus"},
+		{"model", "Ctrl + A  Ctrl + C", Linux, "ctrl+a ctrl+c"},
+		{"model", "home", Android, "KEYCODE_HOME"},
+		{"model", "enter", Android, "KEYCODE_ENTER"},
+
+		{"android", "KEYCODE_BACK", Android, "KEYCODE_BACK"},
+		{"android", "KEYCODE_MOVE_HOME", Android, "KEYCODE_MOVE_HOME"},
+		{"android", "KEYCODE_HOME", Android, "KEYCODE_HOME"},
+		{"android", "KEYCODE_CTRL_LEFT+KEYCODE_A", Linux, "ctrl+a"},
+		{"android", "KEYCODE_MOVE_HOME", Linux, "Home"},
+
+		{"vk", "VK_RETURN", Linux, "Return"},
+		{"vk", "VK_CONTROL+VK_C", Windows, "ctrl+c"},
+		{"vk", "VK_OEM_PERIOD", Linux, "period"},
+		{"vk", "VK_OEM_1", Linux, "semicolon"},
+		{"vk", "VK_OEM_PLUS", Linux, "equal"},
+		{"vk", "VK_SHIFT+VK_OEM_7", Windows, "shift+apostrophe"},
+		{"vk", "VK_NUMPAD7", Linux, "7"},
+		{"vk", "VK_ADD", Linux, "plus"},
+		{"vk", "VK_HOME", Android, "KEYCODE_HOME"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect+"/"+tt.in+"/"+string(tt.os), func(t *testing.T) {
+			got, err := Normalize(tt.in, tt.os)
+			if err != nil {
+				t.Fatalf("Normalize() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	tests := []struct {
+		in      string
+		os      OS
+		wantErr error
+	}{
+		{"ctrl+foo", Linux, ErrUnknownKey},
+		{"VK_OEM_102", Windows, ErrUnknownKey},
+		{"ctrl+c", Android, ErrNotRepresentable},
+		{"ctrl+a ctrl+c", Android, ErrNotRepresentable},
+		{"homescreen", Linux, ErrNotRepresentable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			_, err := Normalize(tt.in, tt.os)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Normalize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseEmptyKey(t *testing.T) {
+	_, err := Parse("ctrl+")
+	if err == nil {
+		t.Fatal("Parse() error = nil, want an empty key error")
+	}
+	if errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Parse() error = %v, want the empty key reported on its own", err)
+	}
+}
@@ -0,0 +1,155 @@
+package keys
+
+import (
+	"fmt"
+	"strings"
+)
+
+// key is a canonical key and its names in the supported dialects.
+type key struct {
+	name    string
+	xdotool string
+	android string
+	vk      string
+	// aliases are normalized names, see fold
+	aliases []string
+}
+
+var keyTable = []key{
+	{"enter", "Return", "KEYCODE_ENTER", "VK_RETURN", []string{"enter", "return", "ret", "cr", "numpadenter", "kpenter"}},
+	{"escape", "Escape", "KEYCODE_ESCAPE", "VK_ESCAPE", []string{"escape", "esc"}},
+	{"tab", "Tab", "KEYCODE_TAB", "VK_TAB", []string{"tab"}},
+	{"backspace", "BackSpace", "KEYCODE_DEL", "VK_BACK", []string{"backspace", "bksp", "bs"}},
+	{"delete", "Delete", "KEYCODE_FORWARD_DEL", "VK_DELETE", []string{"delete", "del", "forwarddelete"}},
+	{"space", "space", "KEYCODE_SPACE", "VK_SPACE", []string{"space", "spacebar", " "}},
+	{"up", "Up", "KEYCODE_DPAD_UP", "VK_UP", []string{"up", "arrowup", "uparrow", "dpadup"}},
+	{"down", "Down", "KEYCODE_DPAD_DOWN", "VK_DOWN", []string{"down", "arrowdown", "downarrow", "dpaddown"}},
+	{"left", "Left", "KEYCODE_DPAD_LEFT", "VK_LEFT", []string{"left", "arrowleft", "leftarrow", "dpadleft"}},
+	{"right", "Right", "KEYCODE_DPAD_RIGHT", "VK_RIGHT", []string{"right", "arrowright", "rightarrow", "dpadright"}},
+	{"home", "Home", "KEYCODE_MOVE_HOME", "VK_HOME", []string{"home", "movehome"}},
+	{"end", "End", "KEYCODE_MOVE_END", "VK_END", []string{"end", "moveend"}},
+	{"pageup", "Page_Up", "KEYCODE_PAGE_UP", "VK_PRIOR", []string{"pageup", "pgup", "prior"}},
+	{"pagedown", "Page_Down", "KEYCODE_PAGE_DOWN", "VK_NEXT", []string{"pagedown", "pgdn", "pgdown", "next"}},
+	{"insert", "Insert", "KEYCODE_INSERT", "VK_INSERT", []string{"insert", "ins"}},
+	{"capslock", "Caps_Lock", "KEYCODE_CAPS_LOCK", "VK_CAPITAL", []string{"capslock", "caps", "capital"}},
+	{"numlock", "Num_Lock", "KEYCODE_NUM_LOCK", "VK_NUMLOCK", []string{"numlock"}},
+	{"scrolllock", "Scroll_Lock", "KEYCODE_SCROLL_LOCK", "VK_SCROLL", []string{"scrolllock", "scroll"}},
+	{"pause", "Pause", "KEYCODE_BREAK", "VK_PAUSE", []string{"pause", "break"}},
+	{"printscreen", "Print", "KEYCODE_SYSRQ", "VK_SNAPSHOT", []string{"printscreen", "print", "prtsc", "prtscn", "snapshot", "sysrq"}},
+	{"menu", "Menu", "KEYCODE_MENU", "VK_APPS", []string{"menu", "contextmenu", "apps"}},
+	{"volumeup", "XF86AudioRaiseVolume", "KEYCODE_VOLUME_UP", "VK_VOLUME_UP", []string{"volumeup", "audiovolumeup", "xf86audioraisevolume"}},
+	{"volumedown", "XF86AudioLowerVolume", "KEYCODE_VOLUME_DOWN", "VK_VOLUME_DOWN", []string{"volumedown", "audiovolumedown", "xf86audiolowervolume"}},
+	{"mute", "XF86AudioMute", "KEYCODE_VOLUME_MUTE", "VK_VOLUME_MUTE", []string{"mute", "volumemute", "audiovolumemute", "xf86audiomute"}},
+	{"back", "XF86Back", "KEYCODE_BACK", "VK_BROWSER_BACK", []string{"back", "browserback", "goback", "xf86back"}},
+	{"search", "XF86Search", "KEYCODE_SEARCH", "VK_BROWSER_SEARCH", []string{"search", "browsersearch", "xf86search"}},
+	{"power", "XF86PowerOff", "KEYCODE_POWER", "", []string{"power", "poweroff", "xf86poweroff"}},
+	{"homescreen", "", "KEYCODE_HOME", "", []string{"homescreen", "homebutton", "androidhome", "gohome"}},
+	{"appswitch", "", "KEYCODE_APP_SWITCH", "", []string{"appswitch", "recents", "recentapps", "overview"}},
+	{"camera", "", "KEYCODE_CAMERA", "", []string{"camera"}},
+}
+
+// punctuation maps the punctuation characters to their xdotool keysym, Android and Windows
+// names. VK_OEM_PLUS is the "=" key of a US layout, "+" has no key of its own.
+var punctuation = []struct {
+	char, xdotool, android, vk string
+	aliases                    []string
+}{
+	{",", "comma", "KEYCODE_COMMA", "VK_OEM_COMMA", []string{"comma"}},
+	{".", "period", "KEYCODE_PERIOD", "VK_OEM_PERIOD", []string{"period", "dot"}},
+	{"/", "slash", "KEYCODE_SLASH", "VK_OEM_2", []string{"slash"}},
+	{";", "semicolon", "KEYCODE_SEMICOLON", "VK_OEM_1", []string{"semicolon"}},
+	{"'", "apostrophe", "KEYCODE_APOSTROPHE", "VK_OEM_7", []string{"apostrophe", "quote"}},
+	{"[", "bracketleft", "KEYCODE_LEFT_BRACKET", "VK_OEM_4", []string{"bracketleft", "leftbracket"}},
+	{"]", "bracketright", "KEYCODE_RIGHT_BRACKET", "VK_OEM_6", []string{"bracketright", "rightbracket"}},
+	{"\\", "backslash", "KEYCODE_BACKSLASH", "VK_OEM_5", []string{"backslash"}},
+	{"-", "minus", "KEYCODE_MINUS", "VK_OEM_MINUS", []string{"minus", "hyphen", "dash"}},
+	{"=", "equal", "KEYCODE_EQUALS", "VK_OEM_PLUS", []string{"equal", "equals"}},
+	{"`", "grave", "KEYCODE_GRAVE", "VK_OEM_3", []string{"grave", "backquote", "backtick"}},
+	{"+", "plus", "KEYCODE_PLUS", "", []string{"plus"}},
+}
+
+// vkNumpad maps the Windows keypad keys to the canonical keys they type.
+var vkNumpad = map[string]string{
+	"VK_ADD": "+", "VK_SUBTRACT": "-", "VK_DECIMAL": ".", "VK_DIVIDE": "/",
+}
+
+var (
+	// byAlias, byAndroid and byVK index the canonical keys by their names in each dialect
+	byAlias   = make(map[string]*key)
+	byAndroid = make(map[string]*key)
+	byVK      = make(map[string]*key)
+	byName    = make(map[string]*key)
+)
+
+func init() {
+	keys := keyTable
+	for c := 'a'; c <= 'z'; c++ {
+		s := string(c)
+		keys = append(keys, key{s, s, "KEYCODE_" + strings.ToUpper(s), "VK_" + strings.ToUpper(s), []string{s, "key" + s}})
+	}
+	for c := '0'; c <= '9'; c++ {
+		s := string(c)
+		keys = append(keys, key{s, s, "KEYCODE_" + s, "VK_" + s, []string{s, "digit" + s, "numpad" + s}})
+	}
+	for i := 1; i <= 24; i++ {
+		name := fmt.Sprintf("f%d", i)
+		android := ""
+		if i <= 12 {
+			android = fmt.Sprintf("KEYCODE_F%d", i)
+		}
+		keys = append(keys, key{name, fmt.Sprintf("F%d", i), android, fmt.Sprintf("VK_F%d", i), []string{name}})
+	}
+	for _, p := range punctuation {
+		keys = append(keys, key{p.char, p.xdotool, p.android, p.vk, append([]string{p.char}, p.aliases...)})
+	}
+
+	for i := range keys {
+		k := &keys[i]
+		byName[k.name] = k
+		for _, alias := range k.aliases {
+			byAlias[alias] = k
+		}
+		if k.android != "" {
+			byAndroid[k.android] = k
+		}
+		if k.vk != "" {
+			byVK[k.vk] = k
+		}
+	}
+	for c := '0'; c <= '9'; c++ {
+		byVK["VK_NUMPAD"+string(c)] = byName[string(c)]
+	}
+	for vk, name := range vkNumpad {
+		byVK[vk] = byName[name]
+	}
+}
+
+// modifierAliases are the normalized names of the modifiers, see fold.
+var modifierAliases = map[string]Modifier{
+	"ctrl": Ctrl, "control": Ctrl, "ctl": Ctrl, "lctrl": Ctrl, "rctrl": Ctrl,
+	"controlleft": Ctrl, "controlright": Ctrl, "controll": Ctrl, "controlr": Ctrl, "^": Ctrl,
+	"shift": Shift, "lshift": Shift, "rshift": Shift, "shiftleft": Shift, "shiftright": Shift,
+	"shiftl": Shift, "shiftr": Shift,
+	"alt": Alt, "option": Alt, "opt": Alt, "lalt": Alt, "ralt": Alt, "altleft": Alt, "altright": Alt,
+	"altl": Alt, "altr": Alt, "⌥": Alt,
+	"cmd": Cmd, "command": Cmd, "meta": Cmd, "metaleft": Cmd, "metaright": Cmd, "metal": Cmd,
+	"metar": Cmd, "⌘": Cmd,
+	"super": Super, "win": Super, "windows": Super, "lwin": Super, "rwin": Super, "os": Super,
+	"superl": Super, "superr": Super, "superleft": Super, "superright": Super,
+}
+
+// androidModifiers and vkModifiers map the modifier names of Android and Windows.
+var (
+	androidModifiers = map[string]Modifier{
+		"KEYCODE_CTRL_LEFT": Ctrl, "KEYCODE_CTRL_RIGHT": Ctrl,
+		"KEYCODE_SHIFT_LEFT": Shift, "KEYCODE_SHIFT_RIGHT": Shift,
+		"KEYCODE_ALT_LEFT": Alt, "KEYCODE_ALT_RIGHT": Alt,
+		"KEYCODE_META_LEFT": Cmd, "KEYCODE_META_RIGHT": Cmd,
+	}
+	vkModifiers = map[string]Modifier{
+		"VK_CONTROL": Ctrl, "VK_LCONTROL": Ctrl, "VK_RCONTROL": Ctrl,
+		"VK_SHIFT": Shift, "VK_LSHIFT": Shift, "VK_RSHIFT": Shift,
+		"VK_MENU": Alt, "VK_LMENU": Alt, "VK_RMENU": Alt,
+		"VK_LWIN": Super, "VK_RWIN": Super,
+	}
+)
@@ -0,0 +1,8 @@
+// Package liveview provides an embeddable http.Handler that streams a sandbox screen as MJPEG.
+//
+//	The handler polls PreviewSandbox at a configurable frame rate, overlays the cursor position
+//	and serves the frames to every connected viewer, together with a small HTML page that also
+//	shows the connect details of the sandbox.
+//
+//	http.Handle("/live/", http.StripPrefix("/live", liveview.NewHandler(client, sandboxId, nil)))
+package liveview
@@ -0,0 +1,360 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package liveview
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	defaultFPS         = 2
+	defaultJPEGQuality = 75
+	boundary           = "lybicframe"
+)
+
+// Options configures the live view handler.
+type Options struct {
+	// FPS is the number of screenshots requested per second, defaults to 2
+	FPS float64
+
+	// JPEGQuality is the quality of the re-encoded frames, 1-100, defaults to 75
+	JPEGQuality int
+
+	// HideCursor disables the cursor overlay
+	HideCursor bool
+
+	// ShowEndUserToken renders the end user token in the connect panel. It grants
+	// access to the sandbox, so it is hidden by default.
+	ShowEndUserToken bool
+
+	// HttpClient downloads the screenshots, defaults to http.DefaultClient
+	HttpClient *http.Client
+}
+
+// Handler serves the live view of a single sandbox.
+//
+//	GET /            HTML page with the stream and the connect panel
+//	GET /stream.mjpg multipart/x-mixed-replace MJPEG stream
+//	GET /frame.jpg   latest frame as a single JPEG
+//
+//	Screenshots are only polled while at least one viewer is connected, and are shared
+//	between all viewers.
+type Handler struct {
+	client    lybic.Client
+	sandboxId string
+	opts      Options
+	logger    lybic.Logger
+	mux       *http.ServeMux
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	frame   []byte
+	seq     uint64
+	viewers int
+	stop    context.CancelFunc
+}
+
+// NewHandler creates a live view handler for the sandbox. opts may be nil.
+func NewHandler(client lybic.Client, sandboxId string, opts *Options) *Handler {
+	h := &Handler{
+		client:    client,
+		sandboxId: sandboxId,
+		logger:    lybic.NewEmptyLogger(),
+	}
+	if config := client.GetConfig(); config != nil && config.Logger != nil {
+		h.logger = config.Logger
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.FPS <= 0 {
+		h.opts.FPS = defaultFPS
+	}
+	if h.opts.JPEGQuality <= 0 || h.opts.JPEGQuality > 100 {
+		h.opts.JPEGQuality = defaultJPEGQuality
+	}
+	if h.opts.HttpClient == nil {
+		h.opts.HttpClient = http.DefaultClient
+	}
+	h.cond = sync.NewCond(&h.mu)
+
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc("GET /{$}", h.servePage)
+	h.mux.HandleFunc("GET /stream.mjpg", h.serveStream)
+	h.mux.HandleFunc("GET /frame.jpg", h.serveFrame)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
+	sandbox, err := h.client.GetSandbox(r.Context(), h.sandboxId)
+	if err != nil {
+		http.Error(w, "failed to get sandbox: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	details := sandbox.ConnectDetails
+	if !h.opts.ShowEndUserToken {
+		details.EndUserToken = ""
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := pageTemplate.Execute(w, struct {
+		Sandbox        lybic.GetSandboxResponseDtoSandbox
+		ConnectDetails lybic.GetSandboxResponseDtoConnectDetails
+	}{sandbox.Sandbox, details}); err != nil {
+		h.logger.Errorf("liveview: failed to render page: %v", err)
+	}
+}
+
+func (h *Handler) serveStream(w http.ResponseWriter, r *http.Request) {
+	h.subscribe()
+	defer h.unsubscribe()
+
+	// wake up the wait below when the viewer goes away
+	stop := context.AfterFunc(r.Context(), func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.cond.Broadcast()
+	})
+	defer stop()
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+boundary)
+	w.Header().Set("Cache-Control", "no-store")
+	flusher, _ := w.(http.Flusher)
+
+	var seen uint64
+	for {
+		h.mu.Lock()
+		for h.seq == seen && r.Context().Err() == nil {
+			h.cond.Wait()
+		}
+		frame, seq := h.frame, h.seq
+		h.mu.Unlock()
+		if r.Context().Err() != nil {
+			return
+		}
+		seen = seq
+
+		if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", boundary, len(frame)); err != nil {
+			return
+		}
+		// frame is shared by every viewer, so it must not be appended to
+		if _, err := w.Write(frame); err != nil {
+			return
+		}
+		if _, err := io.WriteString(w, "\r\n"); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *Handler) serveFrame(w http.ResponseWriter, r *http.Request) {
+	frame, err := h.capture(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(frame)
+}
+
+func (h *Handler) subscribe() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.viewers++
+	if h.viewers == 1 {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.stop = cancel
+		go h.poll(ctx)
+	}
+}
+
+func (h *Handler) unsubscribe() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.viewers--
+	if h.viewers == 0 {
+		h.stop()
+		h.stop = nil
+	}
+}
+
+// poll captures frames at the configured rate and publishes them to the viewers.
+func (h *Handler) poll(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / h.opts.FPS))
+	defer ticker.Stop()
+
+	for {
+		frame, err := h.capture(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			h.logger.Warnf("liveview: failed to capture sandbox %s: %v", h.sandboxId, err)
+		} else {
+			h.mu.Lock()
+			h.frame = frame
+			h.seq++
+			h.cond.Broadcast()
+			h.mu.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// capture takes a screenshot, draws the cursor on it and encodes it as JPEG.
+func (h *Handler) capture(ctx context.Context) ([]byte, error) {
+	preview, err := h.client.PreviewSandbox(ctx, h.sandboxId)
+	if err != nil {
+		return nil, err
+	}
+	if preview.ScreenShot == "" {
+		return nil, fmt.Errorf("preview of sandbox %s has no screenshot", h.sandboxId)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, preview.ScreenShot, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.opts.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download screenshot: %s", resp.Status)
+	}
+
+	src, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	if !h.opts.HideCursor {
+		drawCursor(img, preview.CursorPosition)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: h.opts.JPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawCursor draws a crosshair at the cursor position, scaled from screen to image coordinates.
+func drawCursor(img *image.RGBA, pos lybic.SandboxActionResponseDtoCursorPosition) {
+	b := img.Bounds()
+	if pos.ScreenWidth <= 0 || pos.ScreenHeight <= 0 {
+		return
+	}
+	x := b.Min.X + int(pos.X*float32(b.Dx())/pos.ScreenWidth)
+	y := b.Min.Y + int(pos.Y*float32(b.Dy())/pos.ScreenHeight)
+
+	const arm, gap = 12, 3
+	outline := color.RGBA{A: 255}
+	fill := color.RGBA{R: 255, G: 40, B: 40, A: 255}
+	for _, c := range []struct {
+		col   color.RGBA
+		width int
+	}{{outline, 1}, {fill, 0}} {
+		for d := gap; d <= arm; d++ {
+			for w := -c.width; w <= c.width; w++ {
+				img.Set(x+d, y+w, c.col)
+				img.Set(x-d, y+w, c.col)
+				img.Set(x+w, y+d, c.col)
+				img.Set(x+w, y-d, c.col)
+			}
+		}
+	}
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Sandbox.Name}} - Lybic live view</title>
+<style>
+body { font-family: sans-serif; margin: 1em; background: #f6f6f6; }
+main { display: flex; gap: 1em; align-items: flex-start; }
+img { max-width: 75vw; border: 1px solid #ccc; background: #000; }
+table { border-collapse: collapse; font-size: 0.9em; }
+td, th { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Sandbox.Name}} <small>{{.Sandbox.Id}}</small></h1>
+<main>
+<img src="stream.mjpg" alt="live view">
+<section>
+<h2>Connect</h2>
+<table>
+<tr><th>Room ID</th><td>{{.ConnectDetails.RoomId}}</td></tr>
+<tr><th>Shape</th><td>{{.Sandbox.ShapeName}}</td></tr>
+<tr><th>Expires at</th><td>{{.Sandbox.ExpiresAt}}</td></tr>
+{{- if .ConnectDetails.EndUserToken}}
+<tr><th>End user token</th><td><code>{{.ConnectDetails.EndUserToken}}</code></td></tr>
+{{- end}}
+</table>
+<h3>Gateways</h3>
+<table>
+<tr><th>Name</th><th>Type</th><th>Address</th><th>Providers</th></tr>
+{{- range .ConnectDetails.GatewayAddresses}}
+<tr><td>{{.Name}}</td><td>{{.GatewayType}}</td><td>{{.Address}}:{{.Port}}{{.Path}}</td><td>{{range $i, $p := .PreferredProviders}}{{if $i}}, {{end}}{{$p}}{{end}}</td></tr>
+{{- end}}
+</table>
+</section>
+</main>
+</body>
+</html>
+`))
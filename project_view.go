@@ -0,0 +1,145 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrProjectNotFound = errors.New("project not found")
+
+// ProjectClient is a Client scoped to a single project.
+//
+//	Create calls use the project when the dto does not set one, list calls only return
+//	resources of the project. All other calls pass through unchanged, so a ProjectClient
+//	can be used wherever a Client is expected.
+type ProjectClient interface {
+	Client
+
+	// ProjectId returns the project the view is scoped to
+	ProjectId() string
+}
+
+type projectClient struct {
+	Client
+	projectId string
+}
+
+// NewProjectClient returns a view of c scoped to the project. Scoping a view again
+// replaces the project.
+//
+//	Wrap the outermost client, e.g. the one returned by NewBudgetGuard, so that calls
+//	through the view still pass through every wrapper.
+func NewProjectClient(c Client, projectId string) ProjectClient {
+	if view, ok := c.(*projectClient); ok {
+		c = view.Client
+	}
+	return &projectClient{Client: c, projectId: projectId}
+}
+
+func (p *projectClient) ProjectId() string {
+	return p.projectId
+}
+
+func (p *projectClient) ListSandboxes(ctx context.Context) ([]CreateSandboxResponseDto, error) {
+	sandboxes, err := p.Client.ListSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(sandboxes, func(sandbox CreateSandboxResponseDto) bool {
+		return sandbox.ProjectId != p.projectId
+	}), nil
+}
+
+func (p *projectClient) CreateSandbox(ctx context.Context, dto CreateSandboxDto) (*CreateSandboxResponseDto, error) {
+	if dto.ProjectId == "" {
+		dto.ProjectId = p.projectId
+	}
+	return p.Client.CreateSandbox(ctx, dto)
+}
+
+func (p *projectClient) CreateSandboxFromImage(ctx context.Context, dto CreateSandboxFromImageDto) (*CreateSandboxFromImageResponseDto, error) {
+	if dto.ProjectId == nil || *dto.ProjectId == "" {
+		projectId := p.projectId
+		dto.ProjectId = &projectId
+	}
+	return p.Client.CreateSandboxFromImage(ctx, dto)
+}
+
+type projectMcp struct {
+	Mcp
+	projectId string
+}
+
+// ProjectMcp returns a view of the MCP client scoped to the project: CreateMcpServer uses
+// the project when the dto does not set one and ListMcpServers only returns its servers.
+func ProjectMcp(m Mcp, projectId string) Mcp {
+	return &projectMcp{Mcp: m, projectId: projectId}
+}
+
+func (p *projectMcp) ListMcpServers(ctx context.Context) ([]McpServerResponseDto, error) {
+	servers, err := p.Mcp.ListMcpServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(servers, func(server McpServerResponseDto) bool {
+		return server.ProjectId != p.projectId
+	}), nil
+}
+
+func (p *projectMcp) CreateMcpServer(ctx context.Context, dto CreateMcpServerDto) (*McpServerResponseDto, error) {
+	if dto.ProjectId == "" {
+		dto.ProjectId = p.projectId
+	}
+	return p.Mcp.CreateMcpServer(ctx, dto)
+}
+
+// LookupProjectByName returns the project with the given name.
+//
+//	If no project has the name, the error wraps ErrProjectNotFound. Project names are
+//	not unique, the first match is returned.
+func LookupProjectByName(ctx context.Context, c Client, name string) (*SingleProjectResponseDto, error) {
+	projects, err := c.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if project.Name == name {
+			return &project, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrProjectNotFound, name)
+}
+
+// EnsureProject returns the project with the given name, creating it if it does not exist.
+func EnsureProject(ctx context.Context, c Client, name string) (*SingleProjectResponseDto, error) {
+	project, err := LookupProjectByName(ctx, c, name)
+	if err == nil || !errors.Is(err, ErrProjectNotFound) {
+		return project, err
+	}
+	loggerOf(c).Infof("project %q not found, creating it", name)
+	return c.CreateProject(ctx, CreateProjectDto{Name: name})
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReaperInterval    = 5 * time.Minute
+	defaultReaperConcurrency = 4
+)
+
+var ErrReaperPolicyEmpty = errors.New("reaper policy must select sandboxes by name prefix, name pattern or project")
+
+// ReaperPolicy describes which sandboxes a Reaper treats as orphaned.
+//
+//	A sandbox is a candidate only if it matches every selector that is set.
+//	At least one of NamePrefix, NamePattern or ProjectIds must be set, so that
+//	an empty policy can never select the whole organization.
+type ReaperPolicy struct {
+	// NamePrefix selects sandboxes whose name starts with the prefix
+	NamePrefix string
+
+	// NamePattern selects sandboxes whose name matches the regular expression
+	NamePattern *regexp.Regexp
+
+	// ProjectIds selects sandboxes belonging to one of the projects
+	ProjectIds []string
+
+	// MinAge selects sandboxes created at least MinAge ago, zero disables the check
+	MinAge time.Duration
+
+	// Statuses selects sandboxes in one of the statuses, empty matches any status
+	Statuses []SandboxStatus
+
+	// Allowlist holds sandbox IDs or names that are never deleted
+	Allowlist []string
+
+	// Concurrency limits the number of parallel deletions, defaults to 4
+	Concurrency int
+
+	// DryRun reports candidates without deleting them
+	DryRun bool
+
+	// Interval is the time between two passes of Reaper.Run, defaults to 5 minutes
+	Interval time.Duration
+}
+
+// ReapCandidate is a sandbox selected by a ReaperPolicy.
+type ReapCandidate struct {
+	Sandbox CreateSandboxResponseDto
+	// Age is the time elapsed since the sandbox was created
+	Age time.Duration
+}
+
+// ReapReport is the outcome of a single reaper pass.
+type ReapReport struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	DryRun     bool
+	// Scanned is the number of sandboxes returned by ListSandboxes
+	Scanned int
+	// Candidates are the sandboxes matched by the policy, excluding protected ones
+	Candidates []ReapCandidate
+	// Protected are the IDs of matched sandboxes skipped because of the allowlist
+	Protected []string
+	// Deleted are the IDs of sandboxes deleted in this pass
+	Deleted []string
+	// Failed maps sandbox IDs to the error returned by DeleteSandbox, or to
+	// ctx.Err() for candidates skipped because the context was cancelled
+	Failed map[string]error
+}
+
+// Reaper periodically deletes sandboxes left behind by crashed workers.
+type Reaper struct {
+	client Client
+	policy ReaperPolicy
+	logger Logger
+}
+
+// NewReaper creates a Reaper that applies the policy through the client.
+func NewReaper(client Client, policy ReaperPolicy) (*Reaper, error) {
+	if policy.NamePrefix == "" && policy.NamePattern == nil && len(policy.ProjectIds) == 0 {
+		return nil, ErrReaperPolicyEmpty
+	}
+	if policy.Concurrency <= 0 {
+		policy.Concurrency = defaultReaperConcurrency
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = defaultReaperInterval
+	}
+	return &Reaper{
+		client: client,
+		policy: policy,
+		logger: loggerOf(client),
+	}, nil
+}
+
+// Reap runs a single pass: it lists sandboxes, selects candidates and deletes them.
+//
+//	Deletion failures are recorded in ReapReport.Failed, the returned error is
+//	only set when the sandboxes could not be listed.
+func (r *Reaper) Reap(ctx context.Context) (*ReapReport, error) {
+	report := &ReapReport{
+		StartedAt: time.Now(),
+		DryRun:    r.policy.DryRun,
+		Failed:    make(map[string]error),
+	}
+
+	sandboxes, err := r.client.ListSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Scanned = len(sandboxes)
+
+	for _, sandbox := range sandboxes {
+		if !r.policy.matches(sandbox, report.StartedAt) {
+			continue
+		}
+		if r.policy.protects(sandbox) {
+			report.Protected = append(report.Protected, sandbox.Id)
+			continue
+		}
+		report.Candidates = append(report.Candidates, ReapCandidate{
+			Sandbox: sandbox,
+			Age:     report.StartedAt.Sub(sandbox.CreatedAt),
+		})
+	}
+
+	if r.policy.DryRun {
+		for _, candidate := range report.Candidates {
+			r.logger.Infof("reaper (dry run): would delete sandbox %s (%s), age %s",
+				candidate.Sandbox.Id, candidate.Sandbox.Name, candidate.Age.Truncate(time.Second))
+		}
+		report.FinishedAt = time.Now()
+		return report, nil
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, r.policy.Concurrency)
+acquire:
+	for i, candidate := range report.Candidates {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			// Candidates that never got a slot are reported as failed with the
+			// cancellation cause, so the caller can tell them from deleted ones.
+			mu.Lock()
+			for _, skipped := range report.Candidates[i:] {
+				report.Failed[skipped.Sandbox.Id] = ctx.Err()
+			}
+			mu.Unlock()
+			break acquire
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := r.client.DeleteSandbox(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				r.logger.Errorf("reaper: failed to delete sandbox %s: %v", id, err)
+				report.Failed[id] = err
+				return
+			}
+			report.Deleted = append(report.Deleted, id)
+		}(candidate.Sandbox.Id)
+	}
+	wg.Wait()
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// Run reaps immediately and then once per policy interval until ctx is done.
+//
+//	onReport is called after every successful pass and may be nil.
+//	Errors from a single pass are logged and do not stop the loop.
+func (r *Reaper) Run(ctx context.Context, onReport func(*ReapReport)) error {
+	ticker := time.NewTicker(r.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := r.Reap(ctx)
+		if err != nil {
+			r.logger.Errorf("reaper: pass failed: %v", err)
+		} else if onReport != nil {
+			onReport(report)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p ReaperPolicy) matches(sandbox CreateSandboxResponseDto, now time.Time) bool {
+	if p.NamePrefix != "" && !strings.HasPrefix(sandbox.Name, p.NamePrefix) {
+		return false
+	}
+	if p.NamePattern != nil && !p.NamePattern.MatchString(sandbox.Name) {
+		return false
+	}
+	if len(p.ProjectIds) > 0 && !slices.Contains(p.ProjectIds, sandbox.ProjectId) {
+		return false
+	}
+	if p.MinAge > 0 && now.Sub(sandbox.CreatedAt) < p.MinAge {
+		return false
+	}
+	if len(p.Statuses) > 0 && !slices.Contains(p.Statuses, sandbox.GetStatus()) {
+		return false
+	}
+	return true
+}
+
+func (p ReaperPolicy) protects(sandbox CreateSandboxResponseDto) bool {
+	return slices.Contains(p.Allowlist, sandbox.Id) || slices.Contains(p.Allowlist, sandbox.Name)
+}
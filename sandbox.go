@@ -60,6 +60,7 @@ func (c *client) CreateSandbox(ctx context.Context, dto CreateSandboxDto) (*Crea
 	if err := tryToGetDto[CreateSandboxResponseDto](resp, &sandbox); err != nil {
 		return nil, err
 	}
+	c.config.Tracker.created(ResourceSandbox, "", sandbox.Id)
 
 	return &sandbox, nil
 }
@@ -92,7 +93,11 @@ func (c *client) DeleteSandbox(ctx context.Context, sandboxId string) error {
 		return err
 	}
 
-	return tryToGetDto[any](resp, nil)
+	if err := tryToGetDto[any](resp, nil); err != nil {
+		return err
+	}
+	c.config.Tracker.deleted(ResourceSandbox, "", sandboxId)
+	return nil
 }
 
 // ExtendSandbox extends a sandbox's expiration time by its ID.
@@ -148,6 +153,12 @@ func (c *client) PreviewSandbox(ctx context.Context, sandboxId string) (*Sandbox
 func (c *client) ExecuteSandboxAction(ctx context.Context, sandboxId string, dto ExecuteSandboxActionDto) (*SandboxActionResponseDto, error) {
 	c.config.Logger.Info("Executes a computer use or mobile use action on the sandbox", "sandboxId:", sandboxId)
 
+	if dto.Action != nil {
+		if err := dto.Action.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	url := fmt.Sprintf("/api/orgs/%s/sandboxes/%s/actions/execute", c.config.OrgId, sandboxId)
 	resp, err := c.request(ctx, http.MethodPost, url, nil, dto)
 	if err != nil {
@@ -215,6 +226,7 @@ func (c *client) CreateSandboxFromImage(ctx context.Context, dto CreateSandboxFr
 	if err := tryToGetDto[CreateSandboxFromImageResponseDto](resp, &sandbox); err != nil {
 		return nil, err
 	}
+	c.config.Tracker.created(ResourceSandbox, "", sandbox.Sandbox.Id)
 
 	return &sandbox, nil
 }
@@ -264,6 +276,7 @@ func (c *client) CreateHttpPortMapping(ctx context.Context, sandboxId string, ta
 	if err := tryToGetDto[CreateHttpMappingResponseDto](resp, &mapping); err != nil {
 		return nil, err
 	}
+	c.config.Tracker.created(ResourceHttpMapping, sandboxId, targetEndpoint)
 
 	return &mapping, nil
 }
@@ -296,7 +309,11 @@ func (c *client) DeleteHttpPortMapping(ctx context.Context, sandboxId string, ta
 		return err
 	}
 
-	return tryToGetDto[any](resp, nil)
+	if err := tryToGetDto[any](resp, nil); err != nil {
+		return err
+	}
+	c.config.Tracker.deleted(ResourceHttpMapping, sandboxId, targetEndpoint)
+	return nil
 }
 
 // GetHttpPortMapping retrieves an HTTP port mapping for a sandbox.
@@ -331,6 +348,7 @@ func (c *client) CreateSandboxShellCommand(ctx context.Context, sandboxId string
 	if err := tryToGetDto[SandboxShellCommandCreateResponseDto](resp, &shellResponse); err != nil {
 		return nil, err
 	}
+	c.config.Tracker.created(ResourceShellSession, sandboxId, shellResponse.SessionId)
 
 	return &shellResponse, nil
 }
@@ -389,5 +407,9 @@ func (c *client) TerminateSandboxShellCommand(ctx context.Context, sandboxId str
 		return err
 	}
 
-	return tryToGetDto[any](resp, nil)
+	if err := tryToGetDto[any](resp, nil); err != nil {
+		return err
+	}
+	c.config.Tracker.deleted(ResourceShellSession, sandboxId, shellId)
+	return nil
 }
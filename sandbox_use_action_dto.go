@@ -22,10 +22,69 @@
 
 package lybic
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
 type SandboxUseActionDtoActionOneOf interface {
 	MarshalJSON() ([]byte, error)
 	UnmarshalJSON(src []byte) error
 
+	// Validate checks the fields of the action before it is sent
+	Validate() error
+
 	// _internalSandboxUseActionDtoActionOneOf is a dummy method to prevent external implementations
 	_internalSandboxUseActionDtoActionOneOf()
 }
+
+// rawMessageToSandboxUseActionDtoActionOneOf decodes a computer use or mobile use action by its type.
+func rawMessageToSandboxUseActionDtoActionOneOf(rawAction json.RawMessage) (SandboxUseActionDtoActionOneOf, error) {
+	var base struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(rawAction, &base); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal action type: %w", err)
+	}
+
+	prefix, _, _ := strings.Cut(base.Type, ":")
+	switch prefix {
+	case "touch", "android", "mobile":
+		action, err := rawMessageToMobileUseActionDtoActionOneOf(rawAction)
+		if err != nil {
+			return nil, err
+		}
+		return action.(SandboxUseActionDtoActionOneOf), nil
+	default:
+		action, err := rawMessageToComputerUseActionDtoActionOneOf(rawAction)
+		if err != nil {
+			return nil, err
+		}
+		return action.(SandboxUseActionDtoActionOneOf), nil
+	}
+}
+
+// UnmarshalJSON decodes the action by its type, see rawMessageToSandboxUseActionDtoActionOneOf.
+func (o *ExecuteSandboxActionDto) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Action                json.RawMessage `json:"action"`
+		IncludeScreenShot     *bool           `json:"includeScreenShot,omitempty"`
+		IncludeCursorPosition *bool           `json:"includeCursorPosition,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	o.Action = nil
+	if len(raw.Action) > 0 && string(raw.Action) != "null" {
+		action, err := rawMessageToSandboxUseActionDtoActionOneOf(raw.Action)
+		if err != nil {
+			return err
+		}
+		o.Action = action
+	}
+	o.IncludeScreenShot = raw.IncludeScreenShot
+	o.IncludeCursorPosition = raw.IncludeCursorPosition
+	return nil
+}
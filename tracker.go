@@ -0,0 +1,385 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+var ErrTrackerNotBound = errors.New("resource tracker is not installed on a client, set Config.Tracker before calling NewClient")
+
+// ResourceKind identifies the type of resource recorded by a ResourceTracker.
+type ResourceKind string
+
+const (
+	ResourceSandbox      ResourceKind = "sandbox"
+	ResourceHttpMapping  ResourceKind = "httpMapping"
+	ResourceShellSession ResourceKind = "shellSession"
+	ResourceMcpServer    ResourceKind = "mcpServer"
+)
+
+// TrackedResource is a resource created through a client with a ResourceTracker installed.
+type TrackedResource struct {
+	Kind ResourceKind `json:"kind"`
+	// Id is the sandbox ID, MCP server ID, shell session ID or the target endpoint of an HTTP mapping
+	Id string `json:"id"`
+	// SandboxId is the owning sandbox of HTTP mappings and shell sessions
+	SandboxId string    `json:"sandboxId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (r TrackedResource) key() string {
+	return string(r.Kind) + "/" + r.SandboxId + "/" + r.Id
+}
+
+type journalOp string
+
+const (
+	journalCreate journalOp = "create"
+	journalDelete journalOp = "delete"
+)
+
+type journalEntry struct {
+	Op       journalOp       `json:"op"`
+	Resource TrackedResource `json:"resource"`
+}
+
+// ResourceTracker records every resource created through a client so that it can be
+// removed on shutdown, or after a crash by replaying the on-disk journal.
+//
+//	Install it with Config.Tracker before calling NewClient:
+//
+//	tracker, _ := lybic.NewResourceTracker("/var/run/myapp/lybic.journal")
+//	config.Tracker = tracker
+//	client, _ := lybic.NewClient(config)
+//	_ = tracker.Recover(ctx, "/var/run/myapp/lybic.journal") // leftovers of a previous run
+//	defer tracker.CleanupAll(context.Background())
+type ResourceTracker struct {
+	mu          sync.Mutex
+	resources   map[string]TrackedResource
+	journalPath string
+	journal     *os.File
+	client      *client
+}
+
+// NewResourceTracker creates a tracker. If journalPath is not empty, every change is
+// appended to the file at that path so that it survives a crash.
+func NewResourceTracker(journalPath string) (*ResourceTracker, error) {
+	t := &ResourceTracker{resources: make(map[string]TrackedResource), journalPath: journalPath}
+	if journalPath != "" {
+		f, err := os.OpenFile(journalPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tracker journal: %w", err)
+		}
+		t.journal = f
+	}
+	return t, nil
+}
+
+// Resources returns a snapshot of the tracked resources, oldest first.
+func (t *ResourceTracker) Resources() []TrackedResource {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshot()
+}
+
+// snapshot must be called with t.mu held.
+func (t *ResourceTracker) snapshot() []TrackedResource {
+	resources := make([]TrackedResource, 0, len(t.resources))
+	for _, r := range t.resources {
+		resources = append(resources, r)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].CreatedAt.Before(resources[j].CreatedAt)
+	})
+	return resources
+}
+
+// CleanupAll deletes every tracked resource. Resources that fail to delete stay tracked
+// and the errors are joined into the returned error.
+func (t *ResourceTracker) CleanupAll(ctx context.Context) error {
+	c := t.boundClient()
+	if c == nil {
+		return ErrTrackerNotBound
+	}
+	return t.cleanup(ctx, c, t.Resources())
+}
+
+// Recover replays the journal at journalPath, typically written by a previous process
+// that crashed, and deletes every resource that was created but never deleted.
+//
+//	Leftovers that fail to delete are adopted by the tracker, so CleanupAll retries
+//	them. Leftovers that no longer exist count as deleted. Afterwards the tracker's
+//	own journal is rewritten to hold only the resources that are still tracked.
+func (t *ResourceTracker) Recover(ctx context.Context, journalPath string) error {
+	c := t.boundClient()
+	if c == nil {
+		return ErrTrackerNotBound
+	}
+
+	leftovers, err := readJournal(journalPath)
+	if err != nil {
+		return err
+	}
+	c.config.Logger.Infof("Recovering %d leftover resources from %s", len(leftovers), journalPath)
+
+	t.mu.Lock()
+	for _, r := range leftovers {
+		t.resources[r.key()] = r
+	}
+	t.mu.Unlock()
+
+	err = t.cleanup(ctx, c, leftovers)
+	if compactErr := t.compactJournal(); compactErr != nil {
+		c.config.Logger.Errorf("failed to compact tracker journal: %v", compactErr)
+	}
+	return err
+}
+
+// Close closes the journal file.
+func (t *ResourceTracker) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.journal == nil {
+		return nil
+	}
+	err := t.journal.Close()
+	t.journal = nil
+	return err
+}
+
+// cleanup removes child resources before their sandboxes, since deleting a sandbox
+// already takes its mappings and shell sessions with it.
+func (t *ResourceTracker) cleanup(ctx context.Context, c *client, resources []TrackedResource) error {
+	order := map[ResourceKind]int{
+		ResourceShellSession: 0,
+		ResourceHttpMapping:  1,
+		ResourceMcpServer:    2,
+		ResourceSandbox:      3,
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		return order[resources[i].Kind] < order[resources[j].Kind]
+	})
+
+	var errs []error
+	for _, r := range resources {
+		err := deleteResource(ctx, c, r)
+		if isNotFound(err) {
+			// already gone, e.g. expired or removed with its sandbox
+			t.deleted(r.Kind, r.SandboxId, r.Id)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s %s: %w", r.Kind, r.Id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func deleteResource(ctx context.Context, c *client, r TrackedResource) error {
+	switch r.Kind {
+	case ResourceSandbox:
+		return c.DeleteSandbox(ctx, r.Id)
+	case ResourceHttpMapping:
+		return c.DeleteHttpPortMapping(ctx, r.SandboxId, r.Id)
+	case ResourceShellSession:
+		return c.TerminateSandboxShellCommand(ctx, r.SandboxId, r.Id)
+	case ResourceMcpServer:
+		return (&mcpClient{client: c}).DeleteMcpServer(ctx, r.Id)
+	default:
+		return fmt.Errorf("unknown resource kind: %s", r.Kind)
+	}
+}
+
+func (t *ResourceTracker) bind(c *client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.client = c
+}
+
+func (t *ResourceTracker) boundClient() *client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.client
+}
+
+// created records a new resource. It is a no-op on a nil tracker.
+func (t *ResourceTracker) created(kind ResourceKind, sandboxId, id string) {
+	if t == nil {
+		return
+	}
+	r := TrackedResource{Kind: kind, Id: id, SandboxId: sandboxId, CreatedAt: time.Now()}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resources[r.key()] = r
+	t.appendJournal(journalEntry{Op: journalCreate, Resource: r})
+}
+
+// deleted forgets a resource, and every child resource when a sandbox is deleted.
+// It is a no-op on a nil tracker.
+func (t *ResourceTracker) deleted(kind ResourceKind, sandboxId, id string) {
+	if t == nil {
+		return
+	}
+
+	own := TrackedResource{Kind: kind, Id: id, SandboxId: sandboxId}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// the delete entry is journaled even for untracked resources, so that
+	// resources removed by Recover are not recovered again after another crash
+	delete(t.resources, own.key())
+	t.appendJournal(journalEntry{Op: journalDelete, Resource: own})
+	if kind != ResourceSandbox {
+		return
+	}
+	for key, r := range t.resources {
+		if r.SandboxId == id {
+			delete(t.resources, key)
+			t.appendJournal(journalEntry{Op: journalDelete, Resource: r})
+		}
+	}
+}
+
+// compactJournal replaces the journal with one create entry per tracked resource.
+func (t *ResourceTracker) compactJournal() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.journal == nil {
+		return nil
+	}
+	tmpPath := t.journalPath + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, r := range t.snapshot() {
+		data, err := json.Marshal(journalEntry{Op: journalCreate, Resource: r})
+		if err != nil {
+			f.Close()
+			return err
+		}
+		w.Write(data)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, t.journalPath); err != nil {
+		return err
+	}
+
+	// the old handle still points at the replaced file
+	t.journal.Close()
+	t.journal, err = os.OpenFile(t.journalPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	return err
+}
+
+func (t *ResourceTracker) appendJournal(entry journalEntry) {
+	if t.journal == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err == nil {
+		_, err = t.journal.Write(append(data, '\n'))
+	}
+	if err == nil {
+		err = t.journal.Sync()
+	}
+	if err != nil && t.client != nil {
+		t.client.config.Logger.Errorf("failed to write tracker journal: %v", err)
+	}
+}
+
+func readJournal(journalPath string) ([]TrackedResource, error) {
+	f, err := os.Open(journalPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open tracker journal: %w", err)
+	}
+	defer f.Close()
+
+	live := make(map[string]TrackedResource)
+	seen := make(map[string]bool)
+	var keys []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// a crash can leave a truncated last line behind
+			continue
+		}
+		key := entry.Resource.key()
+		switch entry.Op {
+		case journalCreate:
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+			live[key] = entry.Resource
+		case journalDelete:
+			delete(live, key)
+			if entry.Resource.Kind == ResourceSandbox {
+				for k, r := range live {
+					if r.SandboxId == entry.Resource.Id {
+						delete(live, k)
+					}
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tracker journal: %w", err)
+	}
+
+	var leftovers []TrackedResource
+	for _, key := range keys {
+		if r, ok := live[key]; ok {
+			leftovers = append(leftovers, r)
+		}
+	}
+	return leftovers, nil
+}
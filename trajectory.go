@@ -0,0 +1,356 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+// TrajectoryEntry is one ExecuteSandboxAction or PreviewSandbox call of a trajectory.
+type TrajectoryEntry struct {
+	// Seq is the position of the entry in the trajectory, starting at 1
+	Seq int `json:"seq"`
+	// Op is "ExecuteSandboxAction", "ExecuteComputerUseAction" or "PreviewSandbox"
+	Op        string `json:"op"`
+	SandboxId string `json:"sandboxId"`
+	// Action is the executed action, empty for previews
+	Action     json.RawMessage `json:"action,omitempty"`
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt time.Time       `json:"finishedAt"`
+
+	CursorPosition *SandboxActionResponseDtoCursorPosition `json:"cursorPosition,omitempty"`
+	// ScreenShotUrl is the url returned by the API, it expires after a while
+	ScreenShotUrl string `json:"screenShotUrl,omitempty"`
+	// ScreenShot is the downloaded screenshot, base64 in JSON
+	ScreenShot   []byte `json:"screenShot,omitempty"`
+	ActionResult any    `json:"actionResult,omitempty"`
+
+	// Thoughts and Memory come from the ParseComputerUse or ParseMobileUseModelTextOutput
+	// call that preceded the action, they are set on the first action after the parse
+	Thoughts *string `json:"thoughts,omitempty"`
+	Memory   *string `json:"memory,omitempty"`
+
+	// Error is the error of the call, empty on success
+	Error string `json:"error,omitempty"`
+}
+
+// DecodeAction decodes the recorded action, it returns nil for previews.
+func (e *TrajectoryEntry) DecodeAction() (SandboxUseActionDtoActionOneOf, error) {
+	if len(e.Action) == 0 {
+		return nil, nil
+	}
+	return rawMessageToSandboxUseActionDtoActionOneOf(e.Action)
+}
+
+// RecorderOptions configures a TrajectoryRecorder.
+type RecorderOptions struct {
+	// SkipScreenShots keeps only the screenshot urls instead of downloading the images
+	SkipScreenShots bool
+
+	// HttpClient downloads the screenshots, defaults to http.DefaultClient
+	HttpClient *http.Client
+}
+
+// TrajectoryRecorder is a Client that writes every ExecuteSandboxAction, ExecuteComputerUseAction
+// and PreviewSandbox call to a JSONL trajectory, see NewTrajectoryRecorder.
+type TrajectoryRecorder struct {
+	Client
+	opts   RecorderOptions
+	logger Logger
+
+	mu       sync.Mutex
+	enc      interface{ Encode(v any) error }
+	seq      int
+	thoughts *string
+	memory   *string
+	err      error
+}
+
+// NewTrajectoryRecorder wraps a client so that the sandbox actions it executes are written
+// to w as one TrajectoryEntry per line. opts can be nil.
+//
+//	Recording never fails a call: write and download errors are logged, and the first
+//	one is kept and returned by Err.
+func NewTrajectoryRecorder(client Client, w io.Writer, opts *RecorderOptions) *TrajectoryRecorder {
+	var o RecorderOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.HttpClient == nil {
+		o.HttpClient = http.DefaultClient
+	}
+	return &TrajectoryRecorder{
+		Client: client,
+		opts:   o,
+		logger: loggerOf(client),
+		enc:    json.NewEncoder(w),
+	}
+}
+
+// Err returns the first error that happened while recording.
+func (r *TrajectoryRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *TrajectoryRecorder) ExecuteSandboxAction(ctx context.Context, sandboxId string, dto ExecuteSandboxActionDto) (*SandboxActionResponseDto, error) {
+	entry := TrajectoryEntry{Op: "ExecuteSandboxAction", SandboxId: sandboxId, StartedAt: time.Now()}
+	if dto.Action != nil {
+		action, err := dto.Action.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = action
+	}
+
+	resp, err := r.Client.ExecuteSandboxAction(ctx, sandboxId, dto)
+	r.record(ctx, entry, resp, err, true)
+	return resp, err
+}
+
+func (r *TrajectoryRecorder) ExecuteComputerUseAction(ctx context.Context, sandboxId string, dto ComputerUseActionDto) (*SandboxActionResponseDto, error) {
+	entry := TrajectoryEntry{Op: "ExecuteComputerUseAction", SandboxId: sandboxId, StartedAt: time.Now()}
+	if dto.Action != nil {
+		action, err := dto.Action.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = action
+	}
+
+	resp, err := r.Client.ExecuteComputerUseAction(ctx, sandboxId, dto)
+	r.record(ctx, entry, resp, err, true)
+	return resp, err
+}
+
+func (r *TrajectoryRecorder) PreviewSandbox(ctx context.Context, sandboxId string) (*SandboxActionResponseDto, error) {
+	entry := TrajectoryEntry{Op: "PreviewSandbox", SandboxId: sandboxId, StartedAt: time.Now()}
+
+	resp, err := r.Client.PreviewSandbox(ctx, sandboxId)
+	r.record(ctx, entry, resp, err, false)
+	return resp, err
+}
+
+func (r *TrajectoryRecorder) ParseComputerUse(ctx context.Context, model string, dto ParseTextRequestDto) (*ComputerUseActionResponseDto, error) {
+	resp, err := r.Client.ParseComputerUse(ctx, model, dto)
+	if err == nil {
+		r.setThoughts(resp.Thoughts, resp.Memory)
+	}
+	return resp, err
+}
+
+func (r *TrajectoryRecorder) ParseMobileUseModelTextOutput(ctx context.Context, modelType string, dto ParseTextRequestDto) (*MobileUseActionResponseDto, error) {
+	resp, err := r.Client.ParseMobileUseModelTextOutput(ctx, modelType, dto)
+	if err == nil {
+		r.setThoughts(resp.Thoughts, resp.Memory)
+	}
+	return resp, err
+}
+
+func (r *TrajectoryRecorder) setThoughts(thoughts, memory *string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.thoughts = thoughts
+	r.memory = memory
+}
+
+func (r *TrajectoryRecorder) record(ctx context.Context, entry TrajectoryEntry, resp *SandboxActionResponseDto, callErr error, isAction bool) {
+	entry.FinishedAt = time.Now()
+	var downloadErr error
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	} else if resp != nil {
+		position := resp.CursorPosition
+		if position != (SandboxActionResponseDtoCursorPosition{}) {
+			entry.CursorPosition = &position
+		}
+		entry.ScreenShotUrl = resp.ScreenShot
+		entry.ActionResult = resp.ActionResult
+		if resp.ScreenShot != "" && !r.opts.SkipScreenShots {
+			entry.ScreenShot, downloadErr = r.download(ctx, resp.ScreenShot)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if isAction {
+		entry.Thoughts, entry.Memory = r.thoughts, r.memory
+		r.thoughts, r.memory = nil, nil
+	}
+	r.seq++
+	entry.Seq = r.seq
+	if downloadErr != nil {
+		r.fail(fmt.Errorf("failed to download screenshot of entry %d: %w", entry.Seq, downloadErr))
+	}
+	if err := r.enc.Encode(entry); err != nil {
+		r.fail(fmt.Errorf("failed to write entry %d: %w", entry.Seq, err))
+	}
+}
+
+// fail logs and keeps the first recording error, r.mu must be held.
+func (r *TrajectoryRecorder) fail(err error) {
+	r.logger.Warnf("trajectory: %v", err)
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *TrajectoryRecorder) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.opts.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ReadTrajectory reads the JSONL trajectory written by a TrajectoryRecorder.
+func ReadTrajectory(r io.Reader) ([]TrajectoryEntry, error) {
+	var entries []TrajectoryEntry
+	scanner := bufio.NewScanner(r)
+	// entries embed screenshots, allow lines far beyond the default 64KiB
+	scanner.Buffer(make([]byte, 0, 1<<20), 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry TrajectoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse trajectory line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ReplayDivergence is a difference between the recorded and the replayed outcome of an action.
+type ReplayDivergence struct {
+	// Seq is the sequence number of the recorded entry
+	Seq    int
+	Reason string
+}
+
+// ReplayReport is the outcome of Replay.
+type ReplayReport struct {
+	// Executed is the number of actions that were sent to the sandbox
+	Executed    int
+	Responses   []*SandboxActionResponseDto
+	Divergences []ReplayDivergence
+}
+
+// cursorTolerance is the distance in pixels under which cursor positions are considered equal.
+const cursorTolerance = 2
+
+// Replay re-executes the actions of a trajectory on a sandbox and reports where the outcome
+// differs from the recording. Previews are not replayed.
+//
+//	speed scales the original timing between actions: 1 keeps it, 2 is twice as fast and
+//	0 runs the actions back to back. A divergence is reported when an action fails that
+//	succeeded in the recording or the opposite, when the screen size changed, or when the
+//	cursor ends up somewhere else. Replay only stops early when ctx is done or an action
+//	can not be decoded.
+func Replay(ctx context.Context, c Client, trajectory []TrajectoryEntry, sandboxId string, speed float64) (*ReplayReport, error) {
+	report := &ReplayReport{}
+	var origin, start time.Time
+	for i := range trajectory {
+		entry := &trajectory[i]
+		action, err := entry.DecodeAction()
+		if err != nil {
+			return report, fmt.Errorf("failed to decode action of entry %d: %w", entry.Seq, err)
+		}
+		if action == nil {
+			continue
+		}
+
+		if origin.IsZero() {
+			origin, start = entry.StartedAt, time.Now()
+		} else if speed > 0 {
+			due := start.Add(time.Duration(float64(entry.StartedAt.Sub(origin)) / speed))
+			select {
+			case <-ctx.Done():
+				return report, ctx.Err()
+			case <-time.After(time.Until(due)):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		include := entry.CursorPosition != nil
+		resp, err := c.ExecuteSandboxAction(ctx, sandboxId, ExecuteSandboxActionDto{
+			Action:                action,
+			IncludeScreenShot:     new(bool),
+			IncludeCursorPosition: &include,
+		})
+		report.Executed++
+		report.Responses = append(report.Responses, resp)
+		if reason := divergence(entry, resp, err); reason != "" {
+			report.Divergences = append(report.Divergences, ReplayDivergence{Seq: entry.Seq, Reason: reason})
+		}
+		if err != nil && ctx.Err() != nil {
+			return report, errors.Join(err, ctx.Err())
+		}
+	}
+	return report, nil
+}
+
+func divergence(entry *TrajectoryEntry, resp *SandboxActionResponseDto, err error) string {
+	switch {
+	case err != nil && entry.Error == "":
+		return fmt.Sprintf("action failed on replay: %v", err)
+	case err == nil && entry.Error != "":
+		return fmt.Sprintf("action succeeded on replay but failed in the recording: %s", entry.Error)
+	case err != nil || entry.CursorPosition == nil || resp == nil:
+		return ""
+	}
+
+	want, got := entry.CursorPosition, resp.CursorPosition
+	if want.ScreenWidth != got.ScreenWidth || want.ScreenHeight != got.ScreenHeight {
+		return fmt.Sprintf("screen size %vx%v, recorded %vx%v", got.ScreenWidth, got.ScreenHeight, want.ScreenWidth, want.ScreenHeight)
+	}
+	if math.Abs(float64(want.X-got.X)) > cursorTolerance || math.Abs(float64(want.Y-got.Y)) > cursorTolerance {
+		return fmt.Sprintf("cursor at (%v, %v), recorded (%v, %v)", got.X, got.Y, want.X, want.Y)
+	}
+	return ""
+}
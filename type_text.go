@@ -0,0 +1,228 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrClipboardMismatch is returned when the clipboard read back differs from the text to paste.
+var ErrClipboardMismatch = errors.New("clipboard content differs from the text to paste")
+
+// ErrNoClipboardReceiver is returned on Android when no app handled the clipper.set broadcast.
+var ErrNoClipboardReceiver = errors.New("no clipboard receiver handled the broadcast, is Clipper installed")
+
+// TypeStrategy selects how TypeText enters text.
+type TypeStrategy int
+
+const (
+	// TypeAuto types short ASCII text with keystrokes and pastes anything else. On Android
+	// pasting needs the Clipper app in the sandbox, without it non-ASCII text fails with
+	// ErrNoClipboardReceiver
+	TypeAuto TypeStrategy = iota
+	// TypeKeystrokes sends the text as keyboard:type or mobile:type actions, in chunks
+	TypeKeystrokes
+	// TypePaste sets the clipboard inside the sandbox and sends the paste hotkey
+	TypePaste
+)
+
+const (
+	// typeChunkRunes is the size of the chunks sent as keystrokes
+	typeChunkRunes = 200
+	// pasteChunkRunes keeps the clipboard commands below the Windows command line limit
+	pasteChunkRunes = 4000
+	// autoKeystrokeRunes is the longest text TypeAuto sends as keystrokes on desktops
+	autoKeystrokeRunes = 500
+)
+
+// TypeText enters text into the focused input of a sandbox with the given strategy.
+//
+//	Keystrokes are reliable for ASCII but break on CJK, emoji and long content on some
+//	shapes. Pasting sets the clipboard with ExecSandboxProcess, using xclip on Linux,
+//	PowerShell on Windows and "am broadcast -a clipper.set" on Android (which needs a
+//	clipboard receiver app such as Clipper), then presses ctrl+v or KEYCODE_PASTE. On Linux
+//	and Windows the clipboard is read back and compared to the text before pasting,
+//	ErrClipboardMismatch is returned if it differs. On Android the broadcast must report
+//	that a receiver set the clipboard, ErrNoClipboardReceiver is returned otherwise.
+//
+//	With TypeAuto, long ASCII text falls back to keystrokes if the clipboard can't be set.
+func TypeText(ctx context.Context, c Client, sandboxId string, text string, strategy TypeStrategy) error {
+	if text == "" {
+		return nil
+	}
+	detail, err := c.GetSandbox(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+	osName := detail.Sandbox.Shape.Os
+
+	switch strategy {
+	case TypeKeystrokes:
+		return typeKeystrokes(ctx, c, sandboxId, osName, text)
+	case TypePaste:
+		return typePaste(ctx, c, sandboxId, osName, text)
+	case TypeAuto:
+		ascii := isPlainASCII(text)
+		if ascii && (osName == "Android" || utf8.RuneCountInString(text) <= autoKeystrokeRunes) {
+			return typeKeystrokes(ctx, c, sandboxId, osName, text)
+		}
+		err := typePaste(ctx, c, sandboxId, osName, text)
+		if err != nil && ascii && ctx.Err() == nil {
+			loggerOf(c).Warnf("failed to paste text into sandbox %s, typing it instead: %v", sandboxId, err)
+			return typeKeystrokes(ctx, c, sandboxId, osName, text)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown type strategy %d", strategy)
+	}
+}
+
+func typeKeystrokes(ctx context.Context, c Client, sandboxId, osName, text string) error {
+	for _, chunk := range splitRunes(text, typeChunkRunes) {
+		var action SandboxUseActionDtoActionOneOf = NewKeyboardTypeAction(chunk, true)
+		if osName == "Android" {
+			action = NewMobileTypeAction(chunk)
+		}
+		if _, err := c.ExecuteSandboxAction(ctx, sandboxId, ExecuteSandboxActionDto{Action: action}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func typePaste(ctx context.Context, c Client, sandboxId, osName, text string) error {
+	for _, chunk := range splitRunes(text, pasteChunkRunes) {
+		if err := setClipboard(ctx, c, sandboxId, osName, chunk); err != nil {
+			return fmt.Errorf("failed to set clipboard: %w", err)
+		}
+		var action SandboxUseActionDtoActionOneOf = NewKeyboardHotkeyAction("ctrl+v")
+		if osName == "Android" {
+			action = NewMobileHotkeyAction("KEYCODE_PASTE")
+		}
+		if _, err := c.ExecuteSandboxAction(ctx, sandboxId, ExecuteSandboxActionDto{Action: action}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setClipboard sets the clipboard of the sandbox and checks that it was set, by reading it
+// back on desktops and from the broadcast result on Android.
+func setClipboard(ctx context.Context, c Client, sandboxId, osName, text string) error {
+	var set, get *SandboxProcessRequestDto
+	switch osName {
+	case "Android":
+		set = &SandboxProcessRequestDto{Executable: "am", Args: []string{"broadcast", "-a", "clipper.set", "-e", "text", text}}
+	case "Windows":
+		encoded := base64.StdEncoding.EncodeToString([]byte(text))
+		set = powershell("Set-Clipboard -Value ([Text.Encoding]::UTF8.GetString([Convert]::FromBase64String('" + encoded + "')))")
+		get = powershell("[Convert]::ToBase64String([Text.Encoding]::UTF8.GetBytes((Get-Clipboard -Raw)))")
+	default:
+		// xclip keeps serving the selection in the background, its output must not hold the process open
+		set = &SandboxProcessRequestDto{
+			Executable:  "sh",
+			Args:        []string{"-c", "DISPLAY=${DISPLAY:-:0} xclip -selection clipboard -i >/dev/null 2>&1"},
+			StdinBase64: base64.StdEncoding.EncodeToString([]byte(text)),
+		}
+		get = &SandboxProcessRequestDto{
+			Executable: "sh",
+			Args:       []string{"-c", "DISPLAY=${DISPLAY:-:0} xclip -selection clipboard -o"},
+		}
+	}
+
+	stdout, err := execChecked(ctx, c, sandboxId, *set)
+	if err != nil {
+		return err
+	}
+	if osName == "Android" {
+		// a receiver that set the clipboard completes the broadcast with RESULT_OK (-1)
+		if !strings.Contains(string(stdout), "result=-1") {
+			return ErrNoClipboardReceiver
+		}
+		return nil
+	}
+	stdout, err = execChecked(ctx, c, sandboxId, *get)
+	if err != nil {
+		return err
+	}
+	if osName == "Windows" {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(stdout)))
+		if err != nil {
+			return fmt.Errorf("failed to decode clipboard: %w", err)
+		}
+		stdout = decoded
+	}
+	if string(stdout) != text {
+		return ErrClipboardMismatch
+	}
+	return nil
+}
+
+// execChecked runs a process and returns its stdout, a non-zero exit code is an error.
+func execChecked(ctx context.Context, c Client, sandboxId string, dto SandboxProcessRequestDto) ([]byte, error) {
+	resp, err := c.ExecSandboxProcess(ctx, sandboxId, dto)
+	if err != nil {
+		return nil, err
+	}
+	stdout, _ := base64.StdEncoding.DecodeString(resp.StdoutBase64)
+	if resp.ExitCode != 0 {
+		stderr, _ := base64.StdEncoding.DecodeString(resp.StderrBase64)
+		return nil, fmt.Errorf("%s exited with code %d: %s", dto.Executable, resp.ExitCode, strings.TrimSpace(string(stderr)))
+	}
+	return stdout, nil
+}
+
+// isPlainASCII reports whether text only holds printable ASCII, tabs and line breaks.
+func isPlainASCII(text string) bool {
+	for _, r := range text {
+		if r > unicode.MaxASCII || (!unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t') {
+			return false
+		}
+	}
+	return true
+}
+
+// splitRunes splits text in chunks of at most n runes, without splitting a "\r\n".
+func splitRunes(text string, n int) []string {
+	var chunks []string
+	for len(text) > 0 {
+		end, count := 0, 0
+		for end < len(text) && count < n {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+			count++
+		}
+		if end < len(text) && text[end-1] == '\r' && text[end] == '\n' {
+			end++
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return chunks
+}
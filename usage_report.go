@@ -0,0 +1,326 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lybic/lybic-sdk-go/pkg/json"
+)
+
+// UsageGroup aggregates the resources sharing one value of a dimension.
+type UsageGroup struct {
+	// Key is the project ID, shape name, OS or status
+	Key string `json:"key"`
+	// Name is the project name for project groups, otherwise equal to Key
+	Name             string `json:"name"`
+	Sandboxes        int    `json:"sandboxes"`
+	HttpPortMappings int    `json:"httpPortMappings"`
+	McpServers       int    `json:"mcpServers,omitempty"`
+}
+
+// UsageBucket counts sandboxes whose age or remaining life time falls in a range.
+type UsageBucket struct {
+	Label     string `json:"label"`
+	Sandboxes int    `json:"sandboxes"`
+}
+
+// Usage is an organization usage report, see UsageReport.
+type Usage struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	OrgId       string    `json:"orgId"`
+
+	Sandboxes        int                           `json:"sandboxes"`
+	Projects         int                           `json:"projects"`
+	HttpPortMappings int                           `json:"httpPortMappings"`
+	McpServers       int                           `json:"mcpServers"`
+	MachineImages    int                           `json:"machineImages"`
+	ImageQuota       MachineImagesResponseDtoQuota `json:"imageQuota"`
+
+	ByProject []UsageGroup `json:"byProject"`
+	ByShape   []UsageGroup `json:"byShape"`
+	ByOS      []UsageGroup `json:"byOs"`
+	ByStatus  []UsageGroup `json:"byStatus"`
+
+	// Age is the distribution of the time since creation of the sandboxes
+	Age []UsageBucket `json:"age"`
+	// Expiry is the distribution of the remaining life time of the sandboxes
+	Expiry []UsageBucket `json:"expiry"`
+}
+
+type usageBound struct {
+	label string
+	upTo  time.Duration
+}
+
+var (
+	usageAgeBounds = []usageBound{
+		{"<1h", time.Hour},
+		{"1h-6h", 6 * time.Hour},
+		{"6h-24h", 24 * time.Hour},
+		{"1d-7d", 7 * 24 * time.Hour},
+		{">7d", -1},
+	}
+	usageExpiryBounds = []usageBound{
+		{"expired", 0},
+		{"<15m", 15 * time.Minute},
+		{"15m-1h", time.Hour},
+		{"1h-6h", 6 * time.Hour},
+		{">6h", -1},
+	}
+)
+
+// UsageReport collects the organization usage into a report grouped by project, shape,
+// OS and status. m may be nil, in which case MCP servers are not counted.
+//
+//	The OS of a shape is looked up through GetSandbox once per shape, and HTTP port
+//	mappings are only listed for running sandboxes. Failures of these lookups are
+//	logged and leave the affected values empty rather than failing the report.
+func UsageReport(ctx context.Context, c Client, m Mcp) (*Usage, error) {
+	logger := loggerOf(c)
+	now := time.Now()
+
+	sandboxes, err := c.ListSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := c.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	images, err := c.ListMachineImages(ctx, "org")
+	if err != nil {
+		return nil, err
+	}
+	var servers []McpServerResponseDto
+	if m != nil {
+		if servers, err = m.ListMcpServers(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	usage := &Usage{
+		GeneratedAt:   now,
+		Sandboxes:     len(sandboxes),
+		Projects:      len(projects),
+		McpServers:    len(servers),
+		MachineImages: len(images.Images),
+		ImageQuota:    images.Quota,
+	}
+	if config := c.GetConfig(); config != nil {
+		usage.OrgId = config.OrgId
+	}
+
+	projectNames := make(map[string]string, len(projects))
+	byProject := make(map[string]*UsageGroup, len(projects))
+	for _, project := range projects {
+		projectNames[project.Id] = project.Name
+		byProject[project.Id] = &UsageGroup{Key: project.Id, Name: project.Name}
+	}
+	byShape := make(map[string]*UsageGroup)
+	byOS := make(map[string]*UsageGroup)
+	byStatus := make(map[string]*UsageGroup)
+	group := func(groups map[string]*UsageGroup, key, name string) *UsageGroup {
+		g, ok := groups[key]
+		if !ok {
+			g = &UsageGroup{Key: key, Name: name}
+			groups[key] = g
+		}
+		return g
+	}
+
+	shapeOS := make(map[string]string)
+	usage.Age = newUsageBuckets(usageAgeBounds)
+	usage.Expiry = newUsageBuckets(usageExpiryBounds)
+	for _, sandbox := range sandboxes {
+		os, ok := shapeOS[sandbox.ShapeName]
+		if !ok {
+			if detail, err := c.GetSandbox(ctx, sandbox.Id); err != nil {
+				logger.Warnf("usage report: failed to look up shape %s: %v", sandbox.ShapeName, err)
+			} else {
+				os = detail.Sandbox.Shape.Os
+			}
+			shapeOS[sandbox.ShapeName] = os
+		}
+
+		mappings := 0
+		if sandbox.GetStatus() == SandboxRunning {
+			if list, err := c.ListHttpPortMappings(ctx, sandbox.Id); err != nil {
+				logger.Warnf("usage report: failed to list HTTP port mappings of %s: %v", sandbox.Id, err)
+			} else {
+				mappings = len(list)
+			}
+		}
+		usage.HttpPortMappings += mappings
+
+		status := string(sandbox.GetStatus())
+		for _, g := range []*UsageGroup{
+			group(byProject, sandbox.ProjectId, projectNames[sandbox.ProjectId]),
+			group(byShape, sandbox.ShapeName, sandbox.ShapeName),
+			group(byOS, os, os),
+			group(byStatus, status, status),
+		} {
+			g.Sandboxes++
+			g.HttpPortMappings += mappings
+		}
+
+		addUsageBucket(usage.Age, usageAgeBounds, now.Sub(sandbox.CreatedAt))
+		addUsageBucket(usage.Expiry, usageExpiryBounds, sandbox.ExpiresAt.Sub(now))
+	}
+	for _, server := range servers {
+		group(byProject, server.ProjectId, projectNames[server.ProjectId]).McpServers++
+	}
+
+	usage.ByProject = sortedUsageGroups(byProject)
+	usage.ByShape = sortedUsageGroups(byShape)
+	usage.ByOS = sortedUsageGroups(byOS)
+	usage.ByStatus = sortedUsageGroups(byStatus)
+	return usage, nil
+}
+
+func newUsageBuckets(bounds []usageBound) []UsageBucket {
+	buckets := make([]UsageBucket, len(bounds))
+	for i, b := range bounds {
+		buckets[i].Label = b.label
+	}
+	return buckets
+}
+
+// addUsageBucket counts d in the first bucket whose bound is not below d, the last
+// bound is open ended.
+func addUsageBucket(buckets []UsageBucket, bounds []usageBound, d time.Duration) {
+	for i, b := range bounds {
+		if b.upTo < 0 || d <= b.upTo {
+			buckets[i].Sandboxes++
+			return
+		}
+	}
+}
+
+// sortedUsageGroups orders groups by descending sandbox count, then by key.
+func sortedUsageGroups(groups map[string]*UsageGroup) []UsageGroup {
+	sorted := make([]UsageGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, *g)
+	}
+	slices.SortFunc(sorted, func(a, b UsageGroup) int {
+		return cmp.Or(cmp.Compare(b.Sandboxes, a.Sandboxes), cmp.Compare(a.Key, b.Key))
+	})
+	return sorted
+}
+
+// WriteJSON writes the report as indented JSON.
+func (u *Usage) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteCSV writes the report as CSV with one row per group and bucket.
+//
+//	Columns are dimension, key, name, sandboxes, http_port_mappings and mcp_servers,
+//	bucket rows use the "age" and "expiry" dimensions and leave the last two empty.
+func (u *Usage) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"dimension", "key", "name", "sandboxes", "http_port_mappings", "mcp_servers"})
+	for _, dim := range u.dimensions() {
+		for _, g := range dim.groups {
+			_ = cw.Write([]string{dim.name, g.Key, g.Name, strconv.Itoa(g.Sandboxes),
+				strconv.Itoa(g.HttpPortMappings), strconv.Itoa(g.McpServers)})
+		}
+	}
+	for _, dist := range u.distributions() {
+		for _, b := range dist.buckets {
+			_ = cw.Write([]string{dist.name, b.Label, b.Label, strconv.Itoa(b.Sandboxes), "", ""})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTable writes the report as aligned text tables for humans.
+func (u *Usage) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Usage of %s at %s\n", u.OrgId, u.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(tw, "sandboxes: %d\tprojects: %d\tHTTP port mappings: %d\tMCP servers: %d\n",
+		u.Sandboxes, u.Projects, u.HttpPortMappings, u.McpServers)
+	fmt.Fprintf(tw, "machine images: %d\tquota: %d of %d used\n", u.MachineImages, u.ImageQuota.Used, u.ImageQuota.Limit)
+
+	for _, dim := range u.dimensions() {
+		fmt.Fprintf(tw, "\n%s\tNAME\tSANDBOXES\tHTTP MAPPINGS\tMCP SERVERS\n", dim.title)
+		for _, g := range dim.groups {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", orDash(g.Key), orDash(g.Name), g.Sandboxes, g.HttpPortMappings, g.McpServers)
+		}
+	}
+	for _, dist := range u.distributions() {
+		fmt.Fprintf(tw, "\n%s\tSANDBOXES\n", dist.title)
+		for _, b := range dist.buckets {
+			fmt.Fprintf(tw, "%s\t%d\n", b.Label, b.Sandboxes)
+		}
+	}
+	return tw.Flush()
+}
+
+type usageDimension struct {
+	name, title string
+	groups      []UsageGroup
+}
+
+type usageDistribution struct {
+	name, title string
+	buckets     []UsageBucket
+}
+
+func (u *Usage) dimensions() []usageDimension {
+	return []usageDimension{
+		{"project", "PROJECT", u.ByProject},
+		{"shape", "SHAPE", u.ByShape},
+		{"os", "OS", u.ByOS},
+		{"status", "STATUS", u.ByStatus},
+	}
+}
+
+func (u *Usage) distributions() []usageDistribution {
+	return []usageDistribution{
+		{"age", "AGE", u.Age},
+		{"expiry", "EXPIRES IN", u.Expiry},
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
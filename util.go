@@ -1,6 +1,7 @@
 package lybic
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -20,9 +21,33 @@ func tryToGetDto[T any](resp *http.Response, dto *T) error {
 	var apiErr Error
 	if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
 		return Error{
-			Code:    strconv.Itoa(resp.StatusCode),
-			Message: "request failed with status " + resp.Status + ", and could not decode error response body: " + err.Error(),
+			Code:       strconv.Itoa(resp.StatusCode),
+			Message:    "request failed with status " + resp.Status + ", and could not decode error response body: " + err.Error(),
+			StatusCode: resp.StatusCode,
 		}
 	}
+	apiErr.StatusCode = resp.StatusCode
 	return apiErr
 }
+
+// isNotFound reports whether err is an API error for a resource that does not exist.
+func isNotFound(err error) bool {
+	var apiErr Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// loggerOf returns the logger configured on the client, or a logger that discards everything.
+func loggerOf(c Client) Logger {
+	if config := c.GetConfig(); config != nil && config.Logger != nil {
+		return config.Logger
+	}
+	return emptyLogger{}
+}
+
+// powershell returns the request that runs a PowerShell command in a Windows sandbox.
+func powershell(command string) *SandboxProcessRequestDto {
+	return &SandboxProcessRequestDto{
+		Executable: "powershell",
+		Args:       []string{"-NoProfile", "-NonInteractive", "-Command", command},
+	}
+}
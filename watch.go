@@ -0,0 +1,255 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchPollInterval = 5 * time.Second
+	maxWatchBackoff          = time.Minute
+)
+
+// SandboxEventType is the kind of change reported by a SandboxWatcher.
+type SandboxEventType string
+
+const (
+	SandboxAdded   SandboxEventType = "ADDED"
+	SandboxUpdated SandboxEventType = "UPDATED"
+	SandboxDeleted SandboxEventType = "DELETED"
+)
+
+// SandboxEvent is a change of a watched sandbox.
+type SandboxEvent struct {
+	Type SandboxEventType
+	// Sandbox is the current state, or the last known state for SandboxDeleted
+	Sandbox CreateSandboxResponseDto
+	// Previous is the cached state before an update, nil for SandboxAdded
+	Previous *CreateSandboxResponseDto
+}
+
+// WatchFilter selects the sandboxes observed by WatchSandboxes.
+//
+//	A sandbox that stops matching the filter, e.g. because its status changed
+//	to one that is not selected, is reported as SandboxDeleted.
+type WatchFilter struct {
+	// ProjectId selects sandboxes of a single project, empty selects all projects
+	ProjectId string
+
+	// NamePrefix selects sandboxes whose name starts with the prefix
+	NamePrefix string
+
+	// Statuses selects sandboxes in one of the statuses, empty matches any status
+	Statuses []SandboxStatus
+
+	// PollInterval is the time between two ListSandboxes calls, defaults to 5 seconds
+	PollInterval time.Duration
+
+	// ResyncInterval re-emits every cached sandbox as SandboxUpdated at this interval,
+	// so that consumers can reconcile missed events. Zero disables resync.
+	ResyncInterval time.Duration
+}
+
+func (f WatchFilter) matches(sandbox CreateSandboxResponseDto) bool {
+	if f.ProjectId != "" && sandbox.ProjectId != f.ProjectId {
+		return false
+	}
+	if f.NamePrefix != "" && !strings.HasPrefix(sandbox.Name, f.NamePrefix) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, sandbox.GetStatus()) {
+		return false
+	}
+	return true
+}
+
+// SandboxWatcher keeps a local cache of the sandboxes selected by a WatchFilter and
+// emits an event for every change it observes.
+type SandboxWatcher struct {
+	client Client
+	filter WatchFilter
+	logger Logger
+	events chan SandboxEvent
+
+	mu    sync.RWMutex
+	cache map[string]CreateSandboxResponseDto
+}
+
+// WatchSandboxes lists the sandboxes selected by filter, emits SandboxAdded for each of
+// them and then polls for changes until ctx is done, when the event channel is closed.
+//
+//	An error is only returned when the initial list fails, later failures are logged
+//	and retried with backoff.
+func WatchSandboxes(ctx context.Context, c Client, filter WatchFilter) (*SandboxWatcher, error) {
+	if filter.PollInterval <= 0 {
+		filter.PollInterval = defaultWatchPollInterval
+	}
+
+	w := &SandboxWatcher{
+		client: c,
+		filter: filter,
+		logger: loggerOf(c),
+		events: make(chan SandboxEvent, 64),
+		cache:  make(map[string]CreateSandboxResponseDto),
+	}
+
+	sandboxes, err := c.ListSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := w.diff(sandboxes)
+
+	go w.run(ctx, pending)
+	return w, nil
+}
+
+// Events returns the channel of sandbox events.
+func (w *SandboxWatcher) Events() <-chan SandboxEvent {
+	return w.events
+}
+
+// List returns the cached sandboxes.
+func (w *SandboxWatcher) List() []CreateSandboxResponseDto {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	sandboxes := make([]CreateSandboxResponseDto, 0, len(w.cache))
+	for _, sandbox := range w.cache {
+		sandboxes = append(sandboxes, sandbox)
+	}
+	slices.SortFunc(sandboxes, func(a, b CreateSandboxResponseDto) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return sandboxes
+}
+
+// Get returns the cached sandbox with the given ID.
+func (w *SandboxWatcher) Get(sandboxId string) (CreateSandboxResponseDto, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	sandbox, ok := w.cache[sandboxId]
+	return sandbox, ok
+}
+
+func (w *SandboxWatcher) run(ctx context.Context, pending []SandboxEvent) {
+	defer close(w.events)
+
+	poll := time.NewTimer(w.filter.PollInterval)
+	defer poll.Stop()
+
+	var resync <-chan time.Time
+	if w.filter.ResyncInterval > 0 {
+		ticker := time.NewTicker(w.filter.ResyncInterval)
+		defer ticker.Stop()
+		resync = ticker.C
+	}
+
+	backoff := w.filter.PollInterval
+	for {
+		for _, event := range pending {
+			select {
+			case w.events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		pending = nil
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-resync:
+			pending = w.resyncEvents()
+		case <-poll.C:
+			sandboxes, err := w.client.ListSandboxes(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				backoff = min(backoff*2, maxWatchBackoff)
+				w.logger.Warnf("watch: failed to list sandboxes, retrying in %s: %v", backoff, err)
+				poll.Reset(backoff)
+				continue
+			}
+			backoff = w.filter.PollInterval
+			pending = w.diff(sandboxes)
+			poll.Reset(w.filter.PollInterval)
+		}
+	}
+}
+
+// diff updates the cache from a fresh snapshot and returns the resulting events.
+func (w *SandboxWatcher) diff(sandboxes []CreateSandboxResponseDto) []SandboxEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []SandboxEvent
+	seen := make(map[string]bool, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		if !w.filter.matches(sandbox) {
+			continue
+		}
+		seen[sandbox.Id] = true
+
+		cached, ok := w.cache[sandbox.Id]
+		switch {
+		case !ok:
+			events = append(events, SandboxEvent{Type: SandboxAdded, Sandbox: sandbox})
+		case sandboxChanged(cached, sandbox):
+			previous := cached
+			events = append(events, SandboxEvent{Type: SandboxUpdated, Sandbox: sandbox, Previous: &previous})
+		}
+		w.cache[sandbox.Id] = sandbox
+	}
+	for id, cached := range w.cache {
+		if !seen[id] {
+			events = append(events, SandboxEvent{Type: SandboxDeleted, Sandbox: cached})
+			delete(w.cache, id)
+		}
+	}
+	return events
+}
+
+func (w *SandboxWatcher) resyncEvents() []SandboxEvent {
+	sandboxes := w.List()
+	events := make([]SandboxEvent, len(sandboxes))
+	for i, sandbox := range sandboxes {
+		previous := sandbox
+		events[i] = SandboxEvent{Type: SandboxUpdated, Sandbox: sandbox, Previous: &previous}
+	}
+	return events
+}
+
+func sandboxChanged(a, b CreateSandboxResponseDto) bool {
+	return a.GetStatus() != b.GetStatus() ||
+		a.Name != b.Name ||
+		a.ProjectId != b.ProjectId ||
+		a.ShapeName != b.ShapeName ||
+		!a.ExpiresAt.Equal(b.ExpiresAt)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2019-2025   Beijing Tingyu Technology Co., Ltd.
+// Copyright (c) 2025        Lybic Development Team <team@lybic.ai, lybic@tingyutech.com>
+// Copyright (c) 2025        Lu Yicheng <luyicheng@tingyutech.com>
+//
+// These Terms of Service ("Terms") set forth the rules governing your access to and use of the website lybic.ai
+// ("Website"), our web applications, and other services (collectively, the "Services") provided by Beijing Tingyu
+// Technology Co., Ltd. ("Company," "we," "us," or "our"), a company registered in Haidian District, Beijing. Any
+// breach of these Terms may result in the suspension or termination of your access to the Services.
+// By accessing and using the Services and/or the Website, you represent that you are at least 18 years old,
+// acknowledge that you have read and understood these Terms, and agree to be bound by them. By using or accessing
+// the Services and/or the Website, you further represent and warrant that you have the legal capacity and authority
+// to agree to these Terms, whether as an individual or on behalf of a company. If you do not agree to all of these
+// Terms, do not access or use the Website or Services.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lybic
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseXdotoolKeys parses a key sequence in xdotool key syntax, e.g. "ctrl+shift+t" or
+// "ctrl+a ctrl+c". It returns the chords of the sequence, each being the keys pressed together.
+//
+//	Only the syntax is checked: a key is a single character or a keysym name made of ASCII
+//	letters, digits and underscores (Return, Page_Down, 0xff0d). X defines thousands of
+//	keysyms, names are not looked up and unknown ones are left to the sandbox.
+func parseXdotoolKeys(keys string) ([][]string, error) {
+	fields := strings.Fields(keys)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("must not be empty")
+	}
+	chords := make([][]string, 0, len(fields))
+	for _, field := range fields {
+		chord := strings.Split(field, "+")
+		for _, key := range chord {
+			if key == "" {
+				return nil, fmt.Errorf("empty key in %q, use \"plus\" for the + key", field)
+			}
+			if !isXdotoolKey(key) {
+				return nil, fmt.Errorf("invalid key %q in %q", key, field)
+			}
+		}
+		chords = append(chords, chord)
+	}
+	return chords, nil
+}
+
+func isXdotoolKey(key string) bool {
+	if utf8.RuneCountInString(key) == 1 {
+		return true
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c != '_' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}